@@ -0,0 +1,136 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/flaticols/bump/internal"
+	"github.com/spf13/cobra"
+)
+
+// apiDiffResult is the JSON output of the api-diff command.
+type apiDiffResult struct {
+	Ref           string            `json:"ref"`
+	Kind          string            `json:"kind"`
+	SuggestedPart string            `json:"suggested_part,omitempty"`
+	Changes       []apiChangeResult `json:"changes"`
+}
+
+type apiChangeResult struct {
+	Package string `json:"package"`
+	Name    string `json:"name,omitempty"`
+	Kind    string `json:"kind"`
+	Message string `json:"message"`
+}
+
+func CreateAPIDiffCmd(opts *Options) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "api-diff [ref]",
+		Short: "Compare the exported Go API with the latest tag",
+		Long:  "Type-check the exported API of every public package at the latest semver tag (or the given ref) and in the working tree, classify the differences and suggest the version part to bump",
+		Example: "  bump api-diff          # Compares the working tree with the latest tag\n" +
+			"  bump api-diff v1.2.0   # Compares the working tree with v1.2.0",
+		Args: cobra.MaximumNArgs(1),
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			return prepareHistory(opts, cmd)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ref := ""
+			if len(args) > 0 {
+				ref = args[0]
+			}
+
+			diff, ref, err := diffAPIWithRef(ref)
+			if err != nil {
+				return err
+			}
+
+			result := apiDiffResult{Ref: ref, Kind: diff.Kind().String(), Changes: []apiChangeResult{}}
+			opts.Out.Info("comparing exported API of %s with the working tree", ref)
+			for _, c := range diff.Changes {
+				result.Changes = append(result.Changes, apiChangeResult{Package: c.Package, Name: c.Name, Kind: c.Kind.String(), Message: c.Message})
+				if c.Kind == internal.APIIncompatible {
+					opts.Out.Error("%s: %s", c.Kind, c.String())
+				} else {
+					opts.Out.Ok("%s: %s", c.Kind, c.String())
+				}
+			}
+			opts.Out.Report.Data = &result
+
+			ver, err := semver.NewVersion(ref)
+			if err != nil {
+				opts.Out.Info("exported API changes: %s", diff.Kind())
+				return nil
+			}
+			result.SuggestedPart = apiSuggestedPart(diff.Kind(), ver)
+			opts.Out.Info("exported API changes: %s, suggested bump: %s", diff.Kind(), result.SuggestedPart)
+
+			return nil
+		},
+	}
+
+	return cmd
+}
+
+// diffAPIWithRef compares the exported API of the working tree with ref, or with the latest tag when ref is empty.
+func diffAPIWithRef(ref string) (*internal.APIDiff, string, error) {
+	if !internal.IsGoModule(".") {
+		return nil, ref, fmt.Errorf("no go.mod found, API diff requires a Go module")
+	}
+
+	if ref == "" {
+		tag, err := internal.LatestTag()
+		if err != nil {
+			return nil, ref, err
+		}
+		ref = tag
+	}
+
+	diff, err := internal.DiffGoAPI(ref)
+	return diff, ref, err
+}
+
+// apiSuggestedPart returns the smallest version part that may be bumped for the given kind of API change.
+// Before v1.0.0 incompatible changes only require a minor bump.
+func apiSuggestedPart(kind internal.APIChangeKind, ver *semver.Version) semVerPart {
+	switch kind {
+	case internal.APIIncompatible:
+		if ver.Major() == 0 {
+			return minor
+		}
+		return major
+	case internal.APICompatible:
+		return minor
+	default:
+		return patch
+	}
+}
+
+// partRank orders version parts by significance.
+func partRank(part semVerPart) int {
+	switch part {
+	case major:
+		return 2
+	case minor:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// apiCheck verifies that the version part being bumped is large enough for the exported API changes since ver.
+func apiCheck(opts *Options, part semVerPart, ver *semver.Version) error {
+	opts.Out.BeginCheck("api")
+	diff, _, err := diffAPIWithRef("")
+	if err != nil {
+		return failCheck(opts, "api", err)
+	}
+
+	required := apiSuggestedPart(diff.Kind(), ver)
+	if partRank(part) < partRank(required) {
+		return failCheck(opts, "api", fmt.Errorf("%s bump with %s API changes, %s bump required", part, diff.Kind(), required))
+	}
+
+	opts.Out.Check("api", CheckOk, "exported API changes (%s) allow a %s bump", diff.Kind(), part)
+	return nil
+}
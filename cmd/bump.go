@@ -6,6 +6,7 @@ import (
 	"os"
 	"os/exec"
 	"runtime/debug"
+	"strings"
 
 	"github.com/Masterminds/semver/v3"
 	"github.com/flaticols/bump/internal"
@@ -18,22 +19,30 @@ const (
 	major semVerPart = "major"
 	minor semVerPart = "minor"
 	patch semVerPart = "patch"
+	// pre creates the next pre-release, e.g. v1.2.4-rc.0 after v1.2.3
+	pre semVerPart = "pre"
+	// auto picks the part from the exported Go API changes since the latest tag
+	auto semVerPart = "auto"
 )
 
 type ColorTextPrinter func(format string, a ...any) string
 type VersionPrinter func(string) string
 
 type GitStater interface {
-	IsDefaultBranch() (string, bool, error)
+	IsDefaultBranch(branches internal.BranchesConfig) (string, bool, error)
 	CheckLocalChanges() (bool, error)
-	CheckRemoteChanges(allowNoRemotes bool) (bool, error)
-	HasUnpushedChanges(currentBranch string) (bool, error)
-	HasRemoteUnfetchedTags() (bool, error)
+	CheckUpstream(allowNoRemotes bool) (internal.Upstream, error)
+	CompareRemoteTags() ([]internal.TagSync, error)
 	GetCurrentVersion() (*semver.Version, error)
-	SetGitTag(string) error
+	GetHeadCommit() (string, error)
+	SetGitTag(tag string, annotated bool) error
 	PushGitTag(string) error
+	CommitFiles(message string, files ...string) error
+	PushCurrentBranch() error
 	RemoveLocalGitTag(string) error
 	RemoveRemoteGitTag(string) error
+	FetchGitTag(tag string, force bool) error
+	ForcePushGitTag(string) error
 }
 
 type Symbols struct {
@@ -58,150 +67,438 @@ type Options struct {
 	RepoDirectory      string
 	Verbose, LocalRepo bool
 	BraveMode          bool //ignore any warning just try to do all the things
+	GoChecks           bool
+	APICheck           bool
+	ChangelogCheck     bool
+	Changelog          bool
 	NoColor            bool
-	Exit               func()
+	Output             string
+	Events             string
+	DryRun             bool
+	CI                 bool
+	GitLabDotenv       string
+	GitLabReleaseNotes string
+	CIEnv              internal.CIEnv
+	PreID              string
+	Config             *internal.Config
+	Out                *Reporter
 }
 
 func CreateRootCmd(opts *Options) *cobra.Command {
 	cmd := &cobra.Command{
-		Use:       "bump [major|minor|patch]",
+		Use:       "bump [major|minor|patch|pre|auto]",
 		Short:     "A command-line tool to easily bump the git tag version of your project using semantic versioning",
 		Long:      `Bump is a lightweight command-line tool that helps you manage semantic versioning tags in Git repositories. It automates version increments following SemVer standards, making it easy to maintain proper versioning in your projects.`,
-		Example:   "  bump         # Bumps patch version (e.g., v1.2.3 -> v1.2.4)\n  bump major   # Bumps major version (e.g., v1.2.3 -> v2.0.0)\n  bump minor   # Bumps minor version (e.g., v1.2.3 -> v1.3.0)\n  bump patch   # Bumps patch version (e.g., v1.2.3 -> v1.2.4)",
+		Example:   "  bump         # Bumps patch version (e.g., v1.2.3 -> v1.2.4)\n  bump major   # Bumps major version (e.g., v1.2.3 -> v2.0.0)\n  bump minor   # Bumps minor version (e.g., v1.2.3 -> v1.3.0)\n  bump patch   # Bumps patch version (e.g., v1.2.3 -> v1.2.4)\n  bump pre     # Creates the next pre-release (e.g., v1.2.3 -> v1.2.4-rc.0)\n  bump auto    # Picks the part from the exported Go API changes",
 		Args:      cobra.OnlyValidArgs,
-		ValidArgs: []string{major, minor, patch},
-		PersistentPreRun: func(cmd *cobra.Command, args []string) {
-			if opts.BraveMode {
-				fmt.Printf("%s brave mode enabled, ignoring warnings and errors\n", opts.P.Symbols.Warning)
+		ValidArgs: []string{major, minor, patch, pre, auto},
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			if err := prepareHistory(opts, cmd); err != nil {
+				return err
 			}
+			return gitStateChecks(opts)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runBump(opts, args)
+		},
+	}
 
-			if opts.Verbose {
-				fmt.Printf("%s working directory: %s\n", opts.P.Symbols.Bullet, opts.RepoDirectory)
-			}
+	cmd.Flags().BoolVar(&opts.GoChecks, "go-checks", false, "run Go release hygiene checks before bumping")
+	cmd.Flags().BoolVar(&opts.APICheck, "api-check", false, "verify the bumped part matches the exported Go API changes")
+	cmd.Flags().BoolVar(&opts.ChangelogCheck, "changelog-check", false, "require a non-empty [Unreleased] section in CHANGELOG.md and release it")
+	cmd.Flags().StringVar(&opts.PreID, "preid", internal.DefaultPreID, "identifier of the first pre-release of a stable version")
+	cmd.Flags().StringVar(&opts.GitLabDotenv, "gitlab-dotenv", "", "write BUMP_VERSION, BUMP_TAG and BUMP_PREVIOUS to a GitLab dotenv report (default under GitLab CI: "+internal.DefaultGitLabDotenv+")")
+	cmd.Flags().StringVar(&opts.GitLabReleaseNotes, "gitlab-release-notes", internal.DefaultGitLabReleaseNotes, "release description written next to the GitLab dotenv report")
+	cmd.Flags().BoolVar(&opts.Changelog, "changelog", false, "prepend the notes generated from the commits since the latest tag to CHANGELOG.md")
 
-			err := internal.SetBumpWd(opts.RepoDirectory)
-			if err != nil {
-				fmt.Println(opts.P.Err(err.Error()))
-				os.Exit(1)
-			}
+	cmd.SetVersionTemplate("{{.Version}}\n")
+	cmd.Version = handleVersionCommand()
 
-			gitStateChecks(opts)
-		},
-		RunE: func(cmd *cobra.Command, args []string) error {
-			ver, err := opts.GitDetailer.GetCurrentVersion()
-			var tagErr internal.SemVerTagError
-			var nextVer *semver.Version
+	return cmd
+}
+
+func runBump(opts *Options, args []string) error {
+	if opts.Changelog && (opts.ChangelogCheck || opts.Config.Checks.Changelog) {
+		return withCode(ErrCodeConfig, fmt.Errorf("--changelog cannot be combined with the changelog check, both rewrite %s", internal.ChangelogFile))
+	}
+
+	if opts.GoChecks || opts.Config.Checks.Go {
+		if err := goReleaseChecks(opts); err != nil {
+			return err
+		}
+	}
+
+	changelogReady := false
+	if opts.ChangelogCheck || opts.Config.Checks.Changelog {
+		var err error
+		changelogReady, err = changelogCheck(opts)
+		if err != nil {
+			return err
+		}
+	}
+
+	ver, noTags, err := currentVersion(opts)
+	if err != nil {
+		return err
+	}
+	if noTags {
+		opts.Out.Info("no tags found, using default version %s", opts.P.Version(internal.DefaultVersion))
+	}
+	previousTag, err := latestTagOrEmpty()
+	if err != nil {
+		return withCode(ErrCodeGit, err)
+	}
+
+	part := getIncPart(args)
+	if part == auto {
+		var kind internal.APIChangeKind
+		part, kind, err = autoPart(ver, noTags)
+		if err != nil {
+			return err
+		}
+		if !noTags {
+			opts.Out.Info("exported API changes: %s, using %s bump", kind, part)
+		}
+	} else if (opts.APICheck || opts.Config.Checks.API) && !noTags && part != pre {
+		if err := apiCheck(opts, part, ver); err != nil {
+			return err
+		}
+	}
+
+	nextVer, err := createNewVersion(part, ver, opts.PreID)
+	if err != nil {
+		return withCode(ErrCodeConfig, err)
+	}
+	tag := opts.P.Version(nextVer.String())
+	hookEnv := internal.HookEnv{NewVersion: nextVer.String(), Tag: tag}
+	opts.Out.Report.Version = nextVer.String()
+	opts.Out.Report.Tag = tag
+
+	if noTags {
+		opts.Out.Ok("set tag %s", tag)
+	} else {
+		hookEnv.PreviousVersion = ver.String()
+		opts.Out.Report.PreviousVersion = ver.String()
+		opts.Out.Info("bump tag %s => %s", opts.P.Version(ver.String()), tag)
+	}
+
+	if !opts.LocalRepo {
+		hookEnv.Remote = internal.DefaultRemote
+	}
+	hookEnv.Commit, err = opts.GitDetailer.GetHeadCommit()
+	if err != nil {
+		return withCode(ErrCodeGit, err)
+	}
+
+	releases, err := prepareReleases(opts)
+	if err != nil {
+		return err
+	}
+
+	// a release commit is pushed to the branch, which a detached HEAD only knows from the CI service
+	if !opts.LocalRepo && (changelogReady || opts.Changelog || len(opts.Config.VersionFiles) > 0) {
+		if _, err := internal.PushRef(); err != nil {
+			return withCode(ErrCodePrecondition, err)
+		}
+	}
+
+	if err := runHooks(opts, internal.PreBump, hookEnv); err != nil {
+		return err
+	}
+
+	var releaseFiles []string
+	if changelogReady {
+		if err := releaseChangelog(opts, nextVer, tag); err != nil {
+			return err
+		}
+		releaseFiles = append(releaseFiles, internal.ChangelogFile)
+	}
+	if opts.Changelog {
+		if err := generateChangelog(opts, nextVer, tag); err != nil {
+			return err
+		}
+		releaseFiles = append(releaseFiles, internal.ChangelogFile)
+	}
+	updated, err := updateVersionFiles(opts, nextVer)
+	if err != nil {
+		return err
+	}
+	releaseFiles = append(releaseFiles, updated...)
+	opts.Out.Report.Files = releaseFiles
+
+	releaseCommit := len(releaseFiles) > 0
+	if releaseCommit {
+		err = opts.GitDetailer.CommitFiles(releaseCommitMessage(tag), releaseFiles...)
+		if err != nil {
+			return withCode(ErrCodeGit, err)
+		}
+		if !opts.DryRun {
+			opts.Out.Ok("release commit created")
+			opts.Out.Event(internal.Event{Type: internal.EventCommitCreated, Tag: tag})
+
+			hookEnv.Commit, err = opts.GitDetailer.GetHeadCommit()
 			if err != nil {
-				if errors.As(err, &tagErr) {
-					if !tagErr.NoTags {
-						fmt.Println(opts.P.Err("tag '%s' is not a valid semver tag", tagErr.Tag))
-						os.Exit(1)
-					}
-
-					fmt.Printf("%s no tags found, using default version %s\n", opts.P.Symbols.Bullet, opts.P.Version(internal.DefaultVersion))
-					ver = semver.MustParse("0.0.0")
-				} else {
-					return err
-				}
+				return withCode(ErrCodeGit, err)
 			}
+		}
+	}
+	opts.Out.Report.Commit = hookEnv.Commit
 
-			nextVer = createNewVersion(getIncPart(args), ver)
-			tag := opts.P.Version(nextVer.String())
+	err = opts.GitDetailer.SetGitTag(tag, opts.Config.Tags.Annotated)
+	if err != nil {
+		return withCode(ErrCodeGit, err)
+	}
+	if !opts.DryRun {
+		opts.Out.Ok("tag %s created", tag)
+		opts.Out.Event(internal.Event{Type: internal.EventTagCreated, Version: nextVer.String(), Tag: tag, Commit: hookEnv.Commit})
+	}
 
-			if err != nil && tagErr.NoTags {
-				fmt.Printf("%s set tag %s\n", opts.P.Symbols.Ok, tag)
-			} else {
-				fmt.Printf("%s bump tag %s => %s\n", opts.P.Symbols.Bullet, opts.P.Version(ver.String()), tag)
-			}
+	rollbackLocal := func() error {
+		return opts.GitDetailer.RemoveLocalGitTag(tag)
+	}
+	if err := runPostHooks(opts, internal.PostTag, hookEnv, rollbackLocal); err != nil {
+		return err
+	}
 
-			err = opts.GitDetailer.SetGitTag(tag)
+	if !opts.LocalRepo {
+		if releaseCommit {
+			pushed := opts.Out.Step(internal.EventPushStarted, internal.EventPushFinished, internal.Event{Name: "HEAD", Remote: internal.DefaultRemote})
+			err = withCode(ErrCodePush, opts.GitDetailer.PushCurrentBranch())
+			pushed(err)
 			if err != nil {
 				return err
 			}
-			fmt.Printf("%s tag %s created\n", opts.P.Symbols.Ok, tag)
+			if !opts.DryRun {
+				opts.Out.Ok("release commit pushed")
+			}
+		}
 
-			if !opts.LocalRepo {
-				err = opts.GitDetailer.PushGitTag(tag)
-				if err != nil {
-					return err
-				}
-				fmt.Printf("%s tag %s pushed\n", opts.P.Symbols.Ok, tag)
+		pushed := opts.Out.Step(internal.EventPushStarted, internal.EventPushFinished, internal.Event{Name: tag, Tag: tag, Remote: internal.DefaultRemote})
+		err = withCode(ErrCodePush, opts.GitDetailer.PushGitTag(tag))
+		pushed(err)
+		if err != nil {
+			return err
+		}
+		if !opts.DryRun {
+			opts.Out.Ok("tag %s pushed", tag)
+			opts.Out.Report.RemotesPushed = append(opts.Out.Report.RemotesPushed, internal.DefaultRemote)
+		}
+
+		rollbackRemote := func() error {
+			if err := opts.GitDetailer.RemoveRemoteGitTag(tag); err != nil {
+				return err
 			}
+			return rollbackLocal()
+		}
+		if err := runPostHooks(opts, internal.PostPush, hookEnv, rollbackRemote); err != nil {
+			return err
+		}
 
-			return nil
-		},
+		if err := createReleases(opts, releases, nextVer, hookEnv, previousTag); err != nil {
+			return err
+		}
 	}
 
-	cmd.SetVersionTemplate("{{.Version}}\n")
-	cmd.Version = handleVersionCommand()
-
-	return cmd
+	return gitlabReport(opts, nextVer, tag, previousTag, hookEnv.PreviousVersion)
 }
 
-func gitStateChecks(opts *Options) {
-	exitIfNotBrave := func() {
-		if !opts.BraveMode {
-			os.Exit(1)
+// prepareRun switches to the repository directory and loads its configuration.
+// Commands that do not modify the repository use it instead of the full preflight.
+func prepareRun(opts *Options, cmd *cobra.Command) error {
+	// the arguments are valid at this point, further errors are not about the usage
+	cmd.SilenceUsage = true
+
+	opts.Out = NewReporter(opts.Output, opts.P, os.Stdout)
+	opts.Out.Report.Command = cmd.Name()
+
+	if opts.Output != OutputText && opts.Output != OutputJSON {
+		return withCode(ErrCodeConfig, fmt.Errorf("unknown output format '%s', use %s or %s", opts.Output, OutputText, OutputJSON))
+	}
+
+	if opts.Events != "" {
+		log, err := internal.OpenEventLog(opts.Events)
+		if err != nil {
+			return withCode(ErrCodeConfig, err)
 		}
+		opts.Out.StartEvents(log)
+	}
+
+	if opts.DryRun {
+		opts.Out.Report.DryRun = true
+		opts.GitDetailer = &dryRunGit{GitStater: opts.GitDetailer, out: opts.Out}
+		opts.Out.Warning("dry run, nothing will be changed")
 	}
 
-	b, yes, err := opts.GitDetailer.IsDefaultBranch()
+	if opts.BraveMode {
+		opts.Out.Warning("brave mode enabled, failed checks are reported as warnings")
+	}
+
+	if opts.Verbose {
+		opts.Out.Info("working directory: %s", opts.RepoDirectory)
+	}
+
+	err := internal.SetBumpWd(opts.RepoDirectory)
 	if err != nil {
-		fmt.Printf("%s %s\n", opts.P.Symbols.Error, err.Error())
-		exitIfNotBrave()
+		return withCode(ErrCodeConfig, err)
+	}
+
+	opts.Config, err = internal.LoadConfig(".")
+	if err != nil {
+		return withCode(ErrCodeConfig, err)
+	}
+
+	detectCI(opts)
+
+	return nil
+}
+
+// failCheck reports a failed check and returns the error that stops the run.
+// In brave mode the check is reported as a warning and the run continues.
+func failCheck(opts *Options, name string, err error) error {
+	if opts.BraveMode {
+		opts.Out.Check(name, CheckWarning, "%s", err.Error())
+		return nil
+	}
+
+	opts.Out.Check(name, CheckFailed, "%s", err.Error())
+	return reported(withCode(ErrCodePrecondition, err))
+}
+
+func gitStateChecks(opts *Options) error {
+	opts.Out.BeginCheck("default_branch")
+	b, yes, err := opts.GitDetailer.IsDefaultBranch(opts.Config.Branches)
+	if err != nil {
+		if err := failCheck(opts, "default_branch", err); err != nil {
+			return err
+		}
 	} else if !yes {
-		fmt.Printf("%s not on default branch (%s)\n", opts.P.Symbols.Error, b)
-		exitIfNotBrave()
+		if err := failCheck(opts, "default_branch", fmt.Errorf("not on the default branch or a releasable branch (%s)", b)); err != nil {
+			return err
+		}
 	} else {
-		fmt.Printf("%s on default branch (%s)\n", opts.P.Symbols.Ok, b)
+		opts.Out.Check("default_branch", CheckOk, "on releasable branch (%s)", b)
 	}
 
+	opts.Out.BeginCheck("local_changes")
 	if yes, err := opts.GitDetailer.CheckLocalChanges(); err != nil {
-		fmt.Printf("%s %s\n", opts.P.Symbols.Error, err.Error())
-		exitIfNotBrave()
+		if err := failCheck(opts, "local_changes", err); err != nil {
+			return err
+		}
 	} else if yes {
-		fmt.Printf("%s uncommitted changes\n", opts.P.Symbols.Error)
-		exitIfNotBrave()
+		if err := failCheck(opts, "local_changes", errors.New("uncommitted changes")); err != nil {
+			return err
+		}
 	} else {
-		fmt.Printf("%s no uncommitted changes\n", opts.P.Symbols.Ok)
+		opts.Out.Check("local_changes", CheckOk, "no uncommitted changes")
 	}
 
-	if yes, err := opts.GitDetailer.CheckRemoteChanges(opts.LocalRepo); err != nil {
-		fmt.Printf("%s %s\n", opts.P.Symbols.Error, err.Error())
-		exitIfNotBrave()
-	} else if yes {
-		fmt.Printf("%s remote changes, pull first\n", opts.P.Symbols.Error)
-		exitIfNotBrave()
+	opts.Out.BeginCheck("remote_changes")
+	up, err := opts.GitDetailer.CheckUpstream(opts.LocalRepo)
+	var noUpstream internal.NoUpstreamError
+	if errors.As(err, &noUpstream) {
+		// nothing can be behind a missing upstream, but commits that were never pushed cannot be released
+		opts.Out.Check("remote_changes", CheckWarning, "branch %s has no upstream branch, remote changes not checked", noUpstream.Branch)
+		if opts.LocalRepo {
+			opts.Out.Check("unpushed_changes", CheckWarning, "%s", err.Error())
+		} else if err := failCheck(opts, "unpushed_changes", err); err != nil {
+			return err
+		}
+	} else if err != nil {
+		if err := failCheck(opts, "remote_changes", err); err != nil {
+			return err
+		}
+		opts.Out.Check("unpushed_changes", CheckWarning, "not checked, %s", err.Error())
 	} else {
-		fmt.Printf("%s no remote changes\n", opts.P.Symbols.Ok)
-	}
+		if up.Behind > 0 {
+			if err := failCheck(opts, "remote_changes", fmt.Errorf("%d commits behind %s, pull first", up.Behind, up.Name)); err != nil {
+				return err
+			}
+		} else {
+			opts.Out.Check("remote_changes", CheckOk, "no remote changes")
+		}
 
-	if yes, err := opts.GitDetailer.HasUnpushedChanges(b); err != nil {
-		fmt.Printf("%s %s\n", opts.P.Symbols.Error, err.Error())
-		exitIfNotBrave()
-	} else if yes {
-		fmt.Printf("%s unpushed changes\n", opts.P.Symbols.Error)
-		exitIfNotBrave()
-	} else {
-		fmt.Printf("%s no unpushed changes\n", opts.P.Symbols.Ok)
+		if up.Ahead > 0 {
+			if err := failCheck(opts, "unpushed_changes", fmt.Errorf("%d commits ahead of %s, push first", up.Ahead, up.Name)); err != nil {
+				return err
+			}
+		} else {
+			opts.Out.Check("unpushed_changes", CheckOk, "no unpushed changes")
+		}
 	}
 
-	// Check for unfetched remote tags
+	// Check for diverged and unfetched remote tags, both from a single ls-remote
 	if !opts.LocalRepo {
-		if yes, err := opts.GitDetailer.HasRemoteUnfetchedTags(); err != nil {
-			fmt.Printf("%s %s\n", opts.P.Symbols.Warning, err.Error())
+		opts.Out.BeginCheck("diverged_tags")
+		tags, compareErr := opts.GitDetailer.CompareRemoteTags()
+		if compareErr != nil {
+			opts.Out.Check("diverged_tags", CheckWarning, "%s", compareErr.Error())
+		} else if diverged := tagsInState(tags, internal.TagDiverged); len(diverged) > 0 {
+			opts.Out.Check("diverged_tags", CheckWarning, "tags diverged from %s: %s, run bump sync",
+				internal.DefaultRemote, strings.Join(diverged, ", "))
+		} else {
+			opts.Out.Check("diverged_tags", CheckOk, "no diverged tags")
+		}
+
+		opts.Out.BeginCheck("remote_tags")
+		yes := len(tagsInState(tags, internal.TagRemoteOnly)) > 0
+		if compareErr != nil {
+			opts.Out.Check("remote_tags", CheckWarning, "%s", compareErr.Error())
+		} else if yes && opts.DryRun {
+			_ = fetchTags(opts)
+			opts.Out.Check("remote_tags", CheckWarning, "remote has new tags, the next version is computed from the local tags")
 		} else if yes {
-			fmt.Printf("%s remote has new tags, fetching tags first\n", opts.P.Symbols.Warning)
-			fetchCmd := exec.Command("git", "fetch", "--tags")
-			if err := fetchCmd.Run(); err != nil {
-				fmt.Printf("%s failed to fetch tags: %s\n", opts.P.Symbols.Error, err.Error())
-				exitIfNotBrave()
+			opts.Out.Warning("remote has new tags, fetching tags first")
+			if err := fetchTags(opts); err != nil {
+				if err := failCheck(opts, "remote_tags", fmt.Errorf("failed to fetch tags: %w", err)); err != nil {
+					return err
+				}
+			} else {
+				opts.Out.Check("remote_tags", CheckOk, "tags fetched successfully")
+			}
+		} else {
+			opts.Out.Check("remote_tags", CheckOk, "no new remote tags")
+		}
+	}
+
+	return nil
+}
+
+// fetchTags fetches the tags of the default remote.
+func fetchTags(opts *Options) error {
+	if opts.DryRun {
+		opts.Out.Plan(PlannedAction{Action: ActionFetch, Command: "git fetch --tags " + internal.DefaultRemote})
+		return nil
+	}
+
+	fetched := opts.Out.Step(internal.EventFetchStarted, internal.EventFetchFinished, internal.Event{Name: "tags", Remote: internal.DefaultRemote})
+	fetchCmd := exec.Command("git", "fetch", "--tags", internal.DefaultRemote)
+	output, err := fetchCmd.CombinedOutput()
+	if err != nil {
+		err = withCode(ErrCodeGit, fmt.Errorf("%v - %s", err, strings.TrimSpace(string(output))))
+	}
+	fetched(err)
+	return err
+}
+
+// goReleaseChecks runs the Go module hygiene checks and reports them alongside the git state checks.
+func goReleaseChecks(opts *Options) error {
+	if !internal.IsGoModule(".") {
+		opts.Out.Check("go", CheckWarning, "no go.mod found, skipping Go checks")
+		return nil
+	}
+
+	for _, check := range internal.GoReleaseChecks(".") {
+		opts.Out.BeginCheck(check.ID)
+		if err := check.Run(); err != nil {
+			if err := failCheck(opts, check.ID, err); err != nil {
+				return err
 			}
-			fmt.Printf("%s tags fetched successfully\n", opts.P.Symbols.Ok)
 		} else {
-			fmt.Printf("%s no new remote tags\n", opts.P.Symbols.Ok)
+			opts.Out.Check(check.ID, CheckOk, "%s", check.Name)
 		}
 	}
+
+	return nil
 }
 
 // handleVersionCommand handles the version command and exits.
@@ -217,18 +514,60 @@ func getIncPart(args []string) semVerPart {
 	return patch
 }
 
-func createNewVersion(incPart semVerPart, ver *semver.Version) *semver.Version {
+// releaseCommitMessage returns the message of the commit that records release changes for the tag.
+func releaseCommitMessage(tag string) string {
+	return fmt.Sprintf("chore(release): %s", tag)
+}
+
+// currentVersion returns the version of the latest semver tag, or 0.0.0 and true when there are no tags yet.
+func currentVersion(opts *Options) (*semver.Version, bool, error) {
+	ver, err := opts.GitDetailer.GetCurrentVersion()
+	if err == nil {
+		return ver, false, nil
+	}
+
+	var tagErr internal.SemVerTagError
+	if !errors.As(err, &tagErr) {
+		return nil, false, withCode(ErrCodeGit, err)
+	}
+	if !tagErr.NoTags {
+		return nil, false, withCode(ErrCodeInvalidTag, fmt.Errorf("tag '%s' is not a valid semver tag", tagErr.Tag))
+	}
+	return semver.MustParse("0.0.0"), true, nil
+}
+
+// autoPart picks the version part to bump from the exported API changes since the latest tag.
+func autoPart(ver *semver.Version, noTags bool) (semVerPart, internal.APIChangeKind, error) {
+	if noTags {
+		return patch, internal.APINone, nil
+	}
+
+	diff, _, err := diffAPIWithRef("")
+	if err != nil {
+		return "", internal.APINone, err
+	}
+
+	return apiSuggestedPart(diff.Kind(), ver), diff.Kind(), nil
+}
+
+func createNewVersion(incPart semVerPart, ver *semver.Version, preID string) (*semver.Version, error) {
 	switch incPart {
 	case major:
 		v := ver.IncMajor()
-		return &v
+		return &v, nil
 	case minor:
 		v := ver.IncMinor()
-		return &v
+		return &v, nil
+	case pre:
+		v, err := internal.IncPrerelease(ver, preID)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pre-release identifier '%s': %w", preID, err)
+		}
+		return &v, nil
 	case patch:
 		fallthrough
 	default:
 		v := ver.IncPatch()
-		return &v
+		return &v, nil
 	}
 }
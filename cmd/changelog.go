@@ -0,0 +1,242 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/flaticols/bump/internal"
+	"github.com/spf13/cobra"
+)
+
+// changelogResult is the JSON output of the changelog command printed to stdout.
+type changelogResult struct {
+	Content string `json:"content"`
+}
+
+func CreateChangelogCmd(opts *Options) *cobra.Command {
+	var all, write bool
+	var templatePath string
+
+	cmd := &cobra.Command{
+		Use:   "changelog",
+		Short: "Generate a changelog from the commits since the latest tag",
+		Long:  "Collect the commits since the latest semver tag, group them by Conventional Commit type and render them to stdout or CHANGELOG.md",
+		Example: "  bump changelog                 # Prints the unreleased changes\n" +
+			"  bump changelog --write         # Prepends the unreleased changes to CHANGELOG.md\n" +
+			"  bump changelog --all --write   # Regenerates the releases of CHANGELOG.md from all tags",
+		Args: cobra.NoArgs,
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			return prepareHistory(opts, cmd)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			tmpl, err := changelogTemplate(opts, templatePath)
+			if err != nil {
+				return err
+			}
+
+			var releases []internal.Release
+			if all {
+				releases, err = internal.ReleaseHistory()
+				if err != nil {
+					return err
+				}
+			} else {
+				previous, err := latestTagOrEmpty()
+				if err != nil {
+					return err
+				}
+				r, err := internal.NewRelease(internal.UnreleasedVersion, "", previous, "HEAD", time.Time{})
+				if err != nil {
+					return err
+				}
+				releases = append(releases, r)
+			}
+
+			out, err := internal.RenderReleases(tmpl, releases)
+			if err != nil {
+				return err
+			}
+
+			if !write {
+				opts.Out.Report.Data = changelogResult{Content: string(out)}
+				opts.Out.Text(string(out))
+				return nil
+			}
+
+			if all {
+				out, err = replaceReleases(out)
+				if err != nil {
+					return err
+				}
+			} else {
+				out, err = prependChangelog(out)
+				if err != nil {
+					return err
+				}
+			}
+
+			if err := writeFile(opts, internal.ChangelogFile, out, "generated changelog"); err != nil {
+				return err
+			}
+			opts.Out.Report.Files = []string{internal.ChangelogFile}
+			if !opts.DryRun {
+				opts.Out.Ok("%s updated", internal.ChangelogFile)
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&all, "all", false, "regenerate the history of all tags")
+	cmd.Flags().BoolVarP(&write, "write", "w", false, "write to CHANGELOG.md instead of stdout")
+	cmd.Flags().StringVar(&templatePath, "template", "", "path to a text/template file rendered for every release")
+
+	return cmd
+}
+
+// changelogCheck verifies that CHANGELOG.md has unreleased entries and reports the result with the other checks.
+// It returns whether the changelog can be released.
+func changelogCheck(opts *Options) (bool, error) {
+	opts.Out.BeginCheck("changelog")
+	data, err := os.ReadFile(internal.ChangelogFile)
+	if err == nil {
+		err = internal.CheckUnreleased(data)
+	}
+
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			err = fmt.Errorf("%s not found", internal.ChangelogFile)
+		}
+		return false, failCheck(opts, "changelog", err)
+	}
+
+	opts.Out.Check("changelog", CheckOk, "%s has unreleased changes", internal.ChangelogFile)
+	return true, nil
+}
+
+// releaseChangelog moves the unreleased entries of CHANGELOG.md into a section for the new version.
+func releaseChangelog(opts *Options, ver *semver.Version, tag string) error {
+	data, err := os.ReadFile(internal.ChangelogFile)
+	if err != nil {
+		return err
+	}
+
+	data, err = internal.ReleaseUnreleased(data, ver.String(), tag, time.Now())
+	if err != nil {
+		return err
+	}
+
+	if err := writeFile(opts, internal.ChangelogFile, data, fmt.Sprintf("release [%s] as %s", internal.UnreleasedVersion, ver)); err != nil {
+		return err
+	}
+	if !opts.DryRun {
+		opts.Out.Ok("%s updated for %s", internal.ChangelogFile, tag)
+	}
+
+	return nil
+}
+
+// generateChangelog prepends the notes of the commits since the latest tag to CHANGELOG.md as the new release.
+func generateChangelog(opts *Options, ver *semver.Version, tag string) error {
+	tmpl, err := changelogTemplate(opts, "")
+	if err != nil {
+		return err
+	}
+
+	previous, err := latestTagOrEmpty()
+	if err != nil {
+		return err
+	}
+
+	r, err := internal.NewRelease(ver.String(), tag, previous, "HEAD", time.Now())
+	if err != nil {
+		return err
+	}
+
+	section, err := internal.RenderReleases(tmpl, []internal.Release{r})
+	if err != nil {
+		return err
+	}
+
+	data, err := prependChangelog(section)
+	if err != nil {
+		return err
+	}
+
+	if err := writeFile(opts, internal.ChangelogFile, data, fmt.Sprintf("add generated notes for %s", tag)); err != nil {
+		return err
+	}
+	if !opts.DryRun {
+		opts.Out.Ok("%s generated for %s", internal.ChangelogFile, tag)
+	}
+
+	return nil
+}
+
+// releaseNotes renders the notes of a release page from the commits between the previous tag and HEAD.
+func releaseNotes(ver *semver.Version, tag, previousTag string) (string, error) {
+	r, err := internal.NewRelease(ver.String(), tag, previousTag, "HEAD", time.Now())
+	if err != nil {
+		return "", err
+	}
+
+	notes, err := internal.RenderReleases(internal.ReleaseNotesTemplate, []internal.Release{r})
+	if err != nil {
+		return "", err
+	}
+	if strings.TrimSpace(string(notes)) == "" {
+		return fmt.Sprintf("Release %s\n", tag), nil
+	}
+	return string(notes), nil
+}
+
+// prependChangelog returns CHANGELOG.md with the section inserted above its first release.
+func prependChangelog(section []byte) ([]byte, error) {
+	existing, err := os.ReadFile(internal.ChangelogFile)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+	return internal.PrependChangelog(existing, section), nil
+}
+
+// replaceReleases returns CHANGELOG.md with its release sections replaced by the regenerated ones.
+func replaceReleases(sections []byte) ([]byte, error) {
+	existing, err := os.ReadFile(internal.ChangelogFile)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+	return internal.ReplaceReleases(existing, sections), nil
+}
+
+// changelogTemplate returns the template given on the command line or in the configuration, if any.
+func changelogTemplate(opts *Options, path string) (string, error) {
+	if path == "" {
+		path = opts.Config.Changelog.Template
+	}
+	if path == "" {
+		return "", nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read changelog template: %w", err)
+	}
+	return string(data), nil
+}
+
+// latestTagOrEmpty returns the latest semver tag, or an empty string when there is none yet.
+func latestTagOrEmpty() (string, error) {
+	tag, err := internal.LatestTag()
+	if err != nil {
+		var tagErr internal.SemVerTagError
+		if errors.As(err, &tagErr) {
+			return "", nil
+		}
+		return "", err
+	}
+	return tag, nil
+}
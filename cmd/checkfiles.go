@@ -0,0 +1,79 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/flaticols/bump/internal"
+	"github.com/spf13/cobra"
+)
+
+// versionFileResult is the JSON output of the check of a single version file.
+type versionFileResult struct {
+	Path    string `json:"path"`
+	Format  string `json:"format"`
+	Version string `json:"version"`
+	Status  string `json:"status"`
+}
+
+func CreateCheckFilesCmd(opts *Options) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:          "check-files",
+		Short:        "Verify that the version files match the latest tag",
+		Long:         "Read every configured version file and compare it with the version of the latest semver tag, without modifying anything",
+		Example:      "  bump check-files   # Exits non-zero when a version file has drifted",
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			return prepareRun(opts, cmd)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(opts.Config.VersionFiles) == 0 {
+				return withCode(ErrCodeConfig, fmt.Errorf("no version files configured in %s", internal.ConfigFileName))
+			}
+
+			ver, noTags, err := currentVersion(opts)
+			if err != nil {
+				return err
+			}
+			if noTags {
+				return withCode(ErrCodePrecondition, errors.New("no release tag yet, the version files are checked against the latest tag"))
+			}
+
+			mismatches := 0
+			results := make([]versionFileResult, 0, len(opts.Config.VersionFiles))
+			var table strings.Builder
+			w := tabwriter.NewWriter(&table, 0, 4, 2, ' ', 0)
+			fmt.Fprintln(w, "FILE\tFORMAT\tVERSION\tSTATUS")
+			for _, f := range opts.Config.VersionFiles {
+				found, status := f.Check(ver)
+				results = append(results, versionFileResult{Path: f.Path, Format: f.Format(), Version: found, Status: status})
+
+				if status != internal.VersionFileOk {
+					mismatches++
+					status = opts.P.Err(status)
+				} else {
+					status = opts.P.Ok(status)
+				}
+				fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", f.Path, f.Format(), found, status)
+			}
+			if err := w.Flush(); err != nil {
+				return err
+			}
+			opts.Out.Text(table.String())
+			opts.Out.Report.Version = ver.String()
+			opts.Out.Report.Data = results
+
+			if mismatches > 0 {
+				return withCode(ErrCodePrecondition, fmt.Errorf("%d of %d version files do not match %s", mismatches, len(opts.Config.VersionFiles), opts.P.Version(ver.String())))
+			}
+			opts.Out.Ok("all version files match %s", opts.P.Version(ver.String()))
+
+			return nil
+		},
+	}
+
+	return cmd
+}
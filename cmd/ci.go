@@ -0,0 +1,79 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/flaticols/bump/internal"
+	"github.com/spf13/cobra"
+)
+
+// detectCI turns on CI mode when a CI service is recognised from the environment or --ci is set.
+func detectCI(opts *Options) {
+	if env, ok := internal.DetectCI(os.Getenv); ok {
+		opts.CI = true
+		opts.CIEnv = env
+	} else if opts.CI {
+		opts.CIEnv.Provider = internal.CIGeneric
+	}
+
+	if opts.CI && opts.Verbose {
+		if opts.CIEnv.Branch != "" {
+			opts.Out.Info("CI mode (%s), building branch %s", opts.CIEnv.Provider, opts.CIEnv.Branch)
+		} else {
+			opts.Out.Info("CI mode (%s)", opts.CIEnv.Provider)
+		}
+	}
+}
+
+// prepareHistory prepares a command that reads the commits and tags of the repository. In CI mode it also
+// fetches the history of a shallow clone, the other commands never reach the remote for it.
+func prepareHistory(opts *Options, cmd *cobra.Command) error {
+	if err := prepareRun(opts, cmd); err != nil {
+		return err
+	}
+	if opts.CI && !opts.LocalRepo {
+		return unshallow(opts)
+	}
+	return nil
+}
+
+// unshallow fetches the tags and the history hidden by a shallow clone, so that the latest version
+// and the commits since it are known. ci.deepen limits the fetched history.
+func unshallow(opts *Options) error {
+	shallow, err := internal.IsShallow()
+	if err != nil {
+		return withCode(ErrCodeGit, err)
+	}
+	if !shallow {
+		return nil
+	}
+
+	args := internal.UnshallowArgs(opts.Config.CI.Deepen)
+	if opts.DryRun {
+		opts.Out.Plan(PlannedAction{Action: ActionFetch, Command: "git " + strings.Join(args, " ")})
+		return nil
+	}
+	if opts.Verbose {
+		opts.Out.Info("shallow clone, fetching tags and history")
+	}
+
+	fetched := opts.Out.Step(internal.EventFetchStarted, internal.EventFetchFinished, internal.Event{Name: "history", Remote: internal.DefaultRemote})
+	output, err := exec.Command("git", args...).CombinedOutput()
+	if err != nil {
+		err = withCode(ErrCodeGit, fmt.Errorf("failed to fetch the history of the shallow clone: %v - %s", err, strings.TrimSpace(string(output))))
+	}
+	fetched(err)
+	return err
+}
+
+// checkPrompt returns an error when a question would have to be asked in CI mode, where nobody can answer it.
+// Brave and dry-run mode answer the questions themselves.
+func checkPrompt(opts *Options, action string) error {
+	if opts.CI && !opts.BraveMode && !opts.DryRun {
+		return withCode(ErrCodePrecondition, fmt.Errorf("%s needs a confirmation, which is not asked in CI mode, use --brave to confirm", action))
+	}
+	return nil
+}
@@ -0,0 +1,295 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/flaticols/bump/internal"
+	"github.com/spf13/cobra"
+)
+
+// Formats of bump diff selected with --format.
+const (
+	diffFormatText     = "text"
+	diffFormatMarkdown = "markdown"
+)
+
+// commitResult is the JSON output of a commit.
+type commitResult struct {
+	Hash        string `json:"hash"`
+	Type        string `json:"type,omitempty"`
+	Scope       string `json:"scope,omitempty"`
+	Description string `json:"description"`
+	Breaking    bool   `json:"breaking,omitempty"`
+}
+
+// commitGroupResult is the JSON output of the commits of one Conventional Commit type.
+type commitGroupResult struct {
+	Type    string         `json:"type,omitempty"`
+	Title   string         `json:"title"`
+	Commits []commitResult `json:"commits"`
+}
+
+// fileResult is the JSON output of a changed file.
+type fileResult struct {
+	Path    string `json:"path"`
+	Added   int    `json:"added"`
+	Deleted int    `json:"deleted"`
+	Binary  bool   `json:"binary,omitempty"`
+}
+
+// dirResult is the JSON output of the changes of a directory.
+type dirResult struct {
+	Dir     string `json:"dir"`
+	Package string `json:"package,omitempty"`
+	Files   int    `json:"files"`
+	Added   int    `json:"added"`
+	Deleted int    `json:"deleted"`
+}
+
+// moduleResult is the JSON output of a go.mod requirement change.
+type moduleResult struct {
+	Path string `json:"path"`
+	Kind string `json:"kind"`
+	From string `json:"from,omitempty"`
+	To   string `json:"to,omitempty"`
+}
+
+// diffResult is the JSON output of bump diff.
+type diffResult struct {
+	From     string              `json:"from"`
+	To       string              `json:"to"`
+	Commits  int                 `json:"commits"`
+	Groups   []commitGroupResult `json:"groups"`
+	Breaking []commitResult      `json:"breaking"`
+	Files    []fileResult        `json:"files"`
+	Dirs     []dirResult         `json:"directories"`
+	Modules  []moduleResult      `json:"modules"`
+}
+
+func CreateDiffCmd(opts *Options) *cobra.Command {
+	var format string
+
+	cmd := &cobra.Command{
+		Use:   "diff [from] [to]",
+		Short: "Show what changed between two releases",
+		Long: "Show the commits grouped by Conventional Commit type, the changed files by directory or Go package and the go.mod requirement changes between two revisions.\n" +
+			"Without arguments the previous tag is compared with the latest tag, with one argument the revision is compared with HEAD.",
+		Example: "  bump diff                      # Previous tag..latest tag\n  bump diff v1.2.0               # v1.2.0..HEAD\n" +
+			"  bump diff v1.2.0 v1.3.0 --format markdown",
+		Args: cobra.MaximumNArgs(2),
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			return prepareHistory(opts, cmd)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if format != diffFormatText && format != diffFormatMarkdown {
+				return withCode(ErrCodeConfig, fmt.Errorf("unknown format '%s', use %s or %s", format, diffFormatText, diffFormatMarkdown))
+			}
+
+			from, to, err := diffRange(args)
+			if err != nil {
+				return err
+			}
+
+			d, err := internal.DiffReleases(from, to)
+			if err != nil {
+				return withCode(ErrCodeGit, err)
+			}
+			opts.Out.Report.Data = newDiffResult(d)
+
+			if format == diffFormatMarkdown {
+				opts.Out.Text(renderDiffMarkdown(d))
+			} else {
+				opts.Out.Text(renderDiffText(opts, d))
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&format, "format", diffFormatText, "output format: text or markdown")
+
+	return cmd
+}
+
+// diffRange resolves the revisions to compare from the arguments, defaulting to the two latest tags.
+func diffRange(args []string) (string, string, error) {
+	switch len(args) {
+	case 2:
+		return args[0], args[1], nil
+	case 1:
+		return args[0], "HEAD", nil
+	}
+
+	tags, err := internal.SemverTags()
+	if err != nil {
+		return "", "", withCode(ErrCodeGit, err)
+	}
+	switch len(tags) {
+	case 0:
+		return "", "HEAD", nil
+	case 1:
+		return "", tags[0], nil
+	default:
+		return tags[len(tags)-2], tags[len(tags)-1], nil
+	}
+}
+
+func newDiffResult(d internal.ReleaseDiff) diffResult {
+	r := diffResult{
+		From:     d.From,
+		To:       d.To,
+		Commits:  len(d.Commits),
+		Groups:   make([]commitGroupResult, 0, len(d.Groups)),
+		Breaking: make([]commitResult, 0, len(d.Breaking)),
+		Files:    make([]fileResult, 0, len(d.Files)),
+		Dirs:     make([]dirResult, 0, len(d.Dirs)),
+		Modules:  make([]moduleResult, 0, len(d.Modules)),
+	}
+	for _, g := range d.Groups {
+		group := commitGroupResult{Type: g.Type, Title: g.Title, Commits: make([]commitResult, 0, len(g.Commits))}
+		for _, c := range g.Commits {
+			group.Commits = append(group.Commits, newCommitResult(c))
+		}
+		r.Groups = append(r.Groups, group)
+	}
+	for _, c := range d.Breaking {
+		r.Breaking = append(r.Breaking, newCommitResult(c))
+	}
+	for _, f := range d.Files {
+		r.Files = append(r.Files, fileResult{Path: f.Path, Added: f.Added, Deleted: f.Deleted, Binary: f.Binary})
+	}
+	for _, s := range d.Dirs {
+		r.Dirs = append(r.Dirs, dirResult{Dir: s.Dir, Package: s.Package, Files: s.Files, Added: s.Added, Deleted: s.Deleted})
+	}
+	for _, m := range d.Modules {
+		r.Modules = append(r.Modules, moduleResult{Path: m.Path, Kind: string(m.Kind), From: m.From, To: m.To})
+	}
+	return r
+}
+
+func newCommitResult(c internal.Commit) commitResult {
+	return commitResult{Hash: c.Hash, Type: c.Type, Scope: c.Scope, Description: c.Description, Breaking: c.Breaking}
+}
+
+// diffTitle names the compared range.
+func diffTitle(d internal.ReleaseDiff) string {
+	if d.From == "" {
+		return "up to " + d.To
+	}
+	return d.From + ".." + d.To
+}
+
+// diffTotals returns the number of changed lines.
+func diffTotals(d internal.ReleaseDiff) (added, deleted int) {
+	for _, f := range d.Files {
+		added += f.Added
+		deleted += f.Deleted
+	}
+	return added, deleted
+}
+
+func renderDiffText(opts *Options, d internal.ReleaseDiff) string {
+	var b strings.Builder
+	added, deleted := diffTotals(d)
+	fmt.Fprintf(&b, "%s: %d commits, %d files changed (%s %s)\n", diffTitle(d), len(d.Commits), len(d.Files),
+		opts.P.Ok("+%d", added), opts.P.Err("-%d", deleted))
+
+	if len(d.Breaking) > 0 {
+		fmt.Fprintf(&b, "\n%s\n", opts.P.Err("BREAKING CHANGES"))
+		for _, c := range d.Breaking {
+			fmt.Fprintf(&b, "  %s %s\n", c.ShortHash, commitLine(c))
+		}
+	}
+	for _, g := range d.Groups {
+		fmt.Fprintf(&b, "\n%s\n", g.Title)
+		for _, c := range g.Commits {
+			fmt.Fprintf(&b, "  %s %s\n", c.ShortHash, commitLine(c))
+		}
+	}
+
+	if len(d.Dirs) > 0 {
+		b.WriteString("\nChanged files\n")
+		w := tabwriter.NewWriter(&b, 0, 4, 2, ' ', 0)
+		for _, s := range d.Dirs {
+			fmt.Fprintf(w, "  %s\t%s\t%d files\t+%d -%d\n", s.Dir, s.Package, s.Files, s.Added, s.Deleted)
+		}
+		_ = w.Flush()
+	}
+
+	if len(d.Modules) > 0 {
+		b.WriteString("\nDependencies\n")
+		w := tabwriter.NewWriter(&b, 0, 4, 2, ' ', 0)
+		for _, m := range d.Modules {
+			fmt.Fprintf(w, "  %s\t%s\t%s\n", m.Kind, m.Path, moduleVersions(m, " => "))
+		}
+		_ = w.Flush()
+	}
+
+	return b.String()
+}
+
+func renderDiffMarkdown(d internal.ReleaseDiff) string {
+	var b strings.Builder
+	added, deleted := diffTotals(d)
+	fmt.Fprintf(&b, "## Changes %s\n\n%d commits, %d files changed (+%d -%d)\n", diffTitle(d), len(d.Commits), len(d.Files), added, deleted)
+
+	if len(d.Breaking) > 0 {
+		b.WriteString("\n### BREAKING CHANGES\n\n")
+		for _, c := range d.Breaking {
+			fmt.Fprintf(&b, "- %s (%s)\n", commitMarkdown(c), c.ShortHash)
+		}
+	}
+	for _, g := range d.Groups {
+		fmt.Fprintf(&b, "\n### %s\n\n", g.Title)
+		for _, c := range g.Commits {
+			fmt.Fprintf(&b, "- %s (%s)\n", commitMarkdown(c), c.ShortHash)
+		}
+	}
+
+	if len(d.Dirs) > 0 {
+		b.WriteString("\n### Changed files\n\n| Directory | Package | Files | Added | Deleted |\n|---|---|---|---|---|\n")
+		for _, s := range d.Dirs {
+			pkg := ""
+			if s.Package != "" {
+				pkg = "`" + s.Package + "`"
+			}
+			fmt.Fprintf(&b, "| `%s` | %s | %d | %d | %d |\n", s.Dir, pkg, s.Files, s.Added, s.Deleted)
+		}
+	}
+
+	if len(d.Modules) > 0 {
+		b.WriteString("\n### Dependencies\n\n")
+		for _, m := range d.Modules {
+			fmt.Fprintf(&b, "- %s `%s` %s\n", strings.ToUpper(string(m.Kind[:1]))+string(m.Kind[1:]), m.Path, moduleVersions(m, " → "))
+		}
+	}
+
+	return b.String()
+}
+
+func commitLine(c internal.Commit) string {
+	if c.Scope != "" {
+		return c.Scope + ": " + c.Description
+	}
+	return c.Description
+}
+
+func commitMarkdown(c internal.Commit) string {
+	if c.Scope != "" {
+		return "**" + c.Scope + ":** " + c.Description
+	}
+	return c.Description
+}
+
+// moduleVersions describes the versions of a requirement change.
+func moduleVersions(m internal.ModuleChange, arrow string) string {
+	switch m.Kind {
+	case internal.ModuleAdded:
+		return m.To
+	case internal.ModuleRemoved:
+		return m.From
+	default:
+		return m.From + arrow + m.To
+	}
+}
@@ -0,0 +1,121 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/flaticols/bump/internal"
+)
+
+// Planned action kinds as reported with --dry-run.
+const (
+	ActionTag       = "tag"
+	ActionDeleteTag = "delete-tag"
+	ActionCommit    = "commit"
+	ActionPush      = "push"
+	ActionFetch     = "fetch"
+	ActionEdit      = "edit"
+	ActionHook      = "hook"
+	ActionRelease   = "release"
+)
+
+// PlannedAction is a change that bump would make but skipped because of --dry-run.
+type PlannedAction struct {
+	Action      string `json:"action"`
+	Command     string `json:"command,omitempty"`
+	Path        string `json:"path,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+// dryRunGit passes the read-only calls to the wrapped git state and records the mutating ones
+// as planned actions instead of running them.
+type dryRunGit struct {
+	GitStater
+	out *Reporter
+}
+
+func (g *dryRunGit) SetGitTag(tag string, annotated bool) error {
+	command := "git tag " + tag
+	if annotated {
+		command = fmt.Sprintf("git tag -a -m %s %s", tag, tag)
+	}
+	g.out.Plan(PlannedAction{Action: ActionTag, Command: command})
+	return nil
+}
+
+func (g *dryRunGit) PushGitTag(tag string) error {
+	g.out.Plan(PlannedAction{Action: ActionPush, Command: fmt.Sprintf("git push %s %s", internal.DefaultRemote, tag)})
+	return nil
+}
+
+func (g *dryRunGit) CommitFiles(message string, files ...string) error {
+	g.out.Plan(PlannedAction{Action: ActionCommit, Command: fmt.Sprintf("git commit -m %q -- %s", message, strings.Join(files, " "))})
+	return nil
+}
+
+func (g *dryRunGit) PushCurrentBranch() error {
+	ref, err := internal.PushRef()
+	if err != nil {
+		return err
+	}
+	g.out.Plan(PlannedAction{Action: ActionPush, Command: fmt.Sprintf("git push %s %s", internal.DefaultRemote, ref)})
+	return nil
+}
+
+func (g *dryRunGit) RemoveLocalGitTag(tag string) error {
+	g.out.Plan(PlannedAction{Action: ActionDeleteTag, Command: "git tag -d " + tag})
+	return nil
+}
+
+func (g *dryRunGit) RemoveRemoteGitTag(tag string) error {
+	g.out.Plan(PlannedAction{Action: ActionDeleteTag, Command: fmt.Sprintf("git push --delete %s %s", internal.DefaultRemote, tag)})
+	return nil
+}
+
+func (g *dryRunGit) FetchGitTag(tag string, force bool) error {
+	refspec := "refs/tags/" + tag + ":refs/tags/" + tag
+	if force {
+		refspec = "+" + refspec
+	}
+	g.out.Plan(PlannedAction{Action: ActionFetch, Command: fmt.Sprintf("git fetch --no-tags %s %s", internal.DefaultRemote, refspec)})
+	return nil
+}
+
+func (g *dryRunGit) ForcePushGitTag(tag string) error {
+	g.out.Plan(PlannedAction{Action: ActionPush, Command: fmt.Sprintf("git push --force %s refs/tags/%s", internal.DefaultRemote, tag)})
+	return nil
+}
+
+// writeFile writes a file that bump changes during a release, keeping its permissions.
+// With --dry-run the edit is only recorded in the plan.
+func writeFile(opts *Options, path string, data []byte, description string) error {
+	if opts.DryRun {
+		opts.Out.Plan(PlannedAction{Action: ActionEdit, Path: path, Description: description})
+		return nil
+	}
+
+	perm := os.FileMode(0o644)
+	if info, err := os.Stat(path); err == nil {
+		perm = info.Mode().Perm()
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
+	if err := os.WriteFile(path, data, perm); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
+
+// runHooks runs the hooks of a phase, or records them in the plan with --dry-run.
+func runHooks(opts *Options, phase internal.HookPhase, env internal.HookEnv) error {
+	if opts.DryRun {
+		for _, command := range opts.Config.Hooks.Commands(phase) {
+			opts.Out.Plan(PlannedAction{Action: ActionHook, Command: command, Description: string(phase)})
+		}
+		return nil
+	}
+	return internal.RunHooks(opts.Config.Hooks, phase, env, opts.Out.ToolOutput())
+}
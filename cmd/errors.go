@@ -0,0 +1,127 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/flaticols/bump/internal"
+)
+
+// ErrorCode identifies the kind of failure in machine-readable output.
+type ErrorCode string
+
+const (
+	ErrCodePrecondition ErrorCode = "precondition_failed"
+	ErrCodeInvalidTag   ErrorCode = "invalid_tag"
+	ErrCodeGit          ErrorCode = "git_failed"
+	ErrCodePush         ErrorCode = "push_rejected"
+	ErrCodeHook         ErrorCode = "hook_failed"
+	ErrCodeAborted      ErrorCode = "user_aborted"
+	ErrCodeConfig       ErrorCode = "invalid_config"
+	ErrCodeRelease      ErrorCode = "release_failed"
+	ErrCodeUnknown      ErrorCode = "error"
+)
+
+// exitCodes maps error codes to the exit status of the process. Errors without a code exit with 1.
+var exitCodes = map[ErrorCode]int{
+	ErrCodeConfig:       2,
+	ErrCodePrecondition: 3,
+	ErrCodeInvalidTag:   4,
+	ErrCodeGit:          5,
+	ErrCodePush:         6,
+	ErrCodeHook:         7,
+	ErrCodeAborted:      8,
+	ErrCodeRelease:      9,
+}
+
+// CodedError attaches an ErrorCode to an error.
+type CodedError struct {
+	Code ErrorCode
+	Err  error
+}
+
+func (e *CodedError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *CodedError) Unwrap() error {
+	return e.Err
+}
+
+// withCode wraps err with the given code, nil stays nil.
+func withCode(code ErrorCode, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &CodedError{Code: code, Err: err}
+}
+
+// reportedError marks an error that has already been printed, e.g. as a failed check.
+type reportedError struct {
+	error
+}
+
+func (e reportedError) Unwrap() error {
+	return e.error
+}
+
+// reported marks err as already printed, nil stays nil.
+func reported(err error) error {
+	if err == nil {
+		return nil
+	}
+	return reportedError{err}
+}
+
+// errorCode returns the code of the error, deriving it from well-known error types when none was attached.
+func errorCode(err error) ErrorCode {
+	var coded *CodedError
+	if errors.As(err, &coded) {
+		return coded.Code
+	}
+
+	var hookErr internal.HookError
+	if errors.As(err, &hookErr) {
+		return ErrCodeHook
+	}
+
+	var tagErr internal.SemVerTagError
+	if errors.As(err, &tagErr) {
+		return ErrCodeInvalidTag
+	}
+
+	return ErrCodeUnknown
+}
+
+// ExitCode returns the exit status of the process for the outcome of a command.
+func ExitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+	if code, ok := exitCodes[errorCode(err)]; ok {
+		return code
+	}
+	return 1
+}
+
+// Finish reports the outcome of the command and returns the exit status of the process.
+func Finish(opts *Options, err error) int {
+	if opts.Out == nil {
+		// the command did not start, e.g. because of an unknown flag
+		opts.Out = NewReporter(opts.Output, opts.P, os.Stdout)
+	}
+
+	var shown reportedError
+	if err != nil && !errors.As(err, &shown) {
+		opts.Out.Fatal(err.Error())
+	}
+
+	if ghErr := githubActions(opts, err, os.Stderr); ghErr != nil && err == nil {
+		err = fmt.Errorf("failed to write the GitHub Actions outputs: %w", ghErr)
+		opts.Out.Fatal(err.Error())
+	}
+	opts.Out.Finish(err)
+
+	return ExitCode(err)
+}
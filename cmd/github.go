@@ -0,0 +1,122 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/flaticols/bump/internal"
+)
+
+// githubActions annotates the failed and warning checks of the run when bump runs in GitHub Actions,
+// and for a bump writes the step outputs and appends the job summary to the files GitHub names
+// in GITHUB_OUTPUT and GITHUB_STEP_SUMMARY.
+func githubActions(opts *Options, err error, stderr io.Writer) error {
+	r := &opts.Out.Report
+	if os.Getenv("GITHUB_ACTIONS") == "true" {
+		githubAnnotations(r, err, stderr)
+	}
+
+	// only the bump itself has a release to report
+	if r.Command != "bump" {
+		return nil
+	}
+
+	if path := os.Getenv("GITHUB_OUTPUT"); path != "" {
+		bumped := err == nil && !r.DryRun && r.Tag != ""
+		outputs, fmtErr := internal.FormatGitHubOutputs([]internal.GitHubOutput{
+			{Name: "previous_version", Value: r.PreviousVersion},
+			{Name: "version", Value: r.Version},
+			{Name: "tag", Value: r.Tag},
+			{Name: "commit", Value: r.Commit},
+			{Name: "bumped", Value: fmt.Sprint(bumped)},
+		})
+		if fmtErr != nil {
+			return fmtErr
+		}
+		if err := internal.AppendFile(path, outputs); err != nil {
+			return err
+		}
+	}
+
+	if path := os.Getenv("GITHUB_STEP_SUMMARY"); path != "" {
+		if err := internal.AppendFile(path, githubSummary(r, err)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// githubAnnotations prints a workflow command for every failed or warning check, and for the error
+// of the run when it did not come from a check.
+func githubAnnotations(r *Report, err error, stderr io.Writer) {
+	failedCheck := false
+	for _, c := range r.Checks {
+		switch c.Status {
+		case CheckFailed:
+			failedCheck = true
+			fmt.Fprintln(stderr, internal.GitHubAnnotation("error", c.Name, c.Message))
+		case CheckWarning:
+			fmt.Fprintln(stderr, internal.GitHubAnnotation("warning", c.Name, c.Message))
+		}
+	}
+	if err != nil && !failedCheck {
+		fmt.Fprintln(stderr, internal.GitHubAnnotation("error", "bump", err.Error()))
+	}
+}
+
+// githubSummary renders the checks and the release of a bump as markdown for the job summary.
+func githubSummary(r *Report, err error) string {
+	var b strings.Builder
+
+	switch {
+	case err != nil:
+		b.WriteString("## bump failed\n\n")
+	case r.PreviousVersion != "":
+		fmt.Fprintf(&b, "## bump v%s → %s\n\n", r.PreviousVersion, r.Tag)
+	default:
+		fmt.Fprintf(&b, "## bump %s\n\n", r.Tag)
+	}
+	if r.DryRun {
+		b.WriteString("Dry run, nothing was changed.\n\n")
+	}
+
+	if len(r.Checks) > 0 {
+		b.WriteString("| Check | Status | Message |\n|---|---|---|\n")
+		for _, c := range r.Checks {
+			fmt.Fprintf(&b, "| %s | %s | %s |\n", c.Name, checkEmoji(c.Status), strings.ReplaceAll(c.Message, "|", `\|`))
+		}
+		b.WriteString("\n")
+	}
+
+	if err == nil && r.Tag != "" {
+		fmt.Fprintf(&b, "- **Tag:** `%s`\n", r.Tag)
+		if r.Commit != "" {
+			fmt.Fprintf(&b, "- **Commit:** `%s`\n", shortCommit(r.Commit))
+		}
+		if len(r.Files) > 0 {
+			fmt.Fprintf(&b, "- **Release commit:** %s\n", "`"+strings.Join(r.Files, "`, `")+"`")
+		}
+		if len(r.RemotesPushed) > 0 {
+			fmt.Fprintf(&b, "- **Pushed to:** %s\n", strings.Join(r.RemotesPushed, ", "))
+		}
+	}
+	if err != nil {
+		fmt.Fprintf(&b, "**Error (%s):** %s\n", errorCode(err), err.Error())
+	}
+
+	return b.String() + "\n"
+}
+
+func checkEmoji(status CheckStatus) string {
+	switch status {
+	case CheckOk:
+		return "✅ ok"
+	case CheckWarning:
+		return "⚠️ warning"
+	default:
+		return "❌ failed"
+	}
+}
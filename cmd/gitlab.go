@@ -0,0 +1,46 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/flaticols/bump/internal"
+)
+
+// gitlabReport writes the dotenv report and the release description of a bump for the following
+// GitLab CI jobs. It runs with --gitlab-dotenv, or under GitLab CI with the default paths.
+func gitlabReport(opts *Options, ver *semver.Version, tag, previousTag, previousVersion string) error {
+	dotenv := opts.GitLabDotenv
+	if dotenv == "" && opts.CIEnv.Provider == internal.CIGitLab {
+		dotenv = internal.DefaultGitLabDotenv
+	}
+	if dotenv == "" {
+		return nil
+	}
+
+	notes, err := releaseNotes(ver, tag, previousTag)
+	if err != nil {
+		return withCode(ErrCodeGit, err)
+	}
+	if err := writeFile(opts, opts.GitLabReleaseNotes, []byte(notes), fmt.Sprintf("release description of %s", tag)); err != nil {
+		return err
+	}
+
+	vars, err := internal.FormatDotenv([]internal.DotenvVar{
+		{Name: "BUMP_VERSION", Value: ver.String()},
+		{Name: "BUMP_TAG", Value: tag},
+		{Name: "BUMP_PREVIOUS", Value: previousVersion},
+		{Name: "BUMP_RELEASE_NOTES", Value: opts.GitLabReleaseNotes},
+	})
+	if err != nil {
+		return err
+	}
+	if err := writeFile(opts, dotenv, []byte(vars), "GitLab dotenv report"); err != nil {
+		return err
+	}
+
+	if !opts.DryRun {
+		opts.Out.Ok("GitLab dotenv report written to %s, release description to %s", dotenv, opts.GitLabReleaseNotes)
+	}
+	return nil
+}
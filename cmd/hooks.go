@@ -0,0 +1,40 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/flaticols/bump/internal"
+	"github.com/flaticols/bump/internal/tui"
+)
+
+// runPostHooks runs the hooks configured for a post phase. When one of them fails the error is reported
+// and, if a rollback is given, the user is offered to undo what has been done so far.
+func runPostHooks(opts *Options, phase internal.HookPhase, env internal.HookEnv, rollback func() error) error {
+	err := runHooks(opts, phase, env)
+	if err == nil {
+		return nil
+	}
+
+	opts.Out.Error("%s", err.Error())
+	if rollback == nil {
+		return reported(err)
+	}
+
+	confirm := tui.AskConfirmation(fmt.Sprintf("Roll back tag %s?", env.Tag),
+		tui.Yes("Yes, roll back"), tui.No("No, keep the tag"), tui.AvoidIf(opts.BraveMode || opts.CI, false))
+	if !confirm {
+		return reported(err)
+	}
+
+	rolledBack := opts.Out.Step(internal.EventRollbackStarted, internal.EventRollbackFinished, internal.Event{Tag: env.Tag})
+	rbErr := rollback()
+	rolledBack(rbErr)
+	if rbErr != nil {
+		opts.Out.Error("failed to roll back tag %s", env.Tag)
+		return errors.Join(err, rbErr)
+	}
+	opts.Out.Ok("tag %s rolled back", env.Tag)
+
+	return reported(err)
+}
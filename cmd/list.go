@@ -0,0 +1,131 @@
+package cmd
+
+import (
+	"encoding/csv"
+	"fmt"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/flaticols/bump/internal"
+	"github.com/spf13/cobra"
+)
+
+// tagResult is the JSON output of a single release in bump list.
+type tagResult struct {
+	Tag                  string    `json:"tag"`
+	Version              string    `json:"version"`
+	Date                 time.Time `json:"date"`
+	Author               string    `json:"author"`
+	Commit               string    `json:"commit"`
+	CommitsSincePrevious int       `json:"commits_since_previous"`
+	Prerelease           bool      `json:"prerelease"`
+	Annotated            bool      `json:"annotated"`
+}
+
+func CreateListCmd(opts *Options) *cobra.Command {
+	var constraint, prefix string
+	var asCSV bool
+
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List the released versions",
+		Long:  "List the semver tags ordered by version with their dates, authors, target commits and the number of commits since the previous version",
+		Example: "  bump list                        # Every release\n  bump list --constraint '^1.2'    # Releases matching a version constraint\n" +
+			"  bump list --prefix tools/        # Releases of the nested module in tools/\n  bump list --csv > releases.csv",
+		Args: cobra.NoArgs,
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			return prepareRun(opts, cmd)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var c *semver.Constraints
+			if constraint != "" {
+				var err error
+				c, err = semver.NewConstraint(constraint)
+				if err != nil {
+					return withCode(ErrCodeConfig, fmt.Errorf("invalid constraint '%s': %w", constraint, err))
+				}
+			}
+
+			tags, err := internal.ListTags(prefix)
+			if err != nil {
+				return withCode(ErrCodeGit, err)
+			}
+
+			results := make([]tagResult, 0, len(tags))
+			for _, t := range tags {
+				if c != nil && !c.Check(t.Version) {
+					continue
+				}
+				results = append(results, tagResult{
+					Tag:                  t.Name,
+					Version:              t.Version.String(),
+					Date:                 t.Date,
+					Author:               t.Author,
+					Commit:               t.Commit,
+					CommitsSincePrevious: t.CommitsSincePrevious,
+					Prerelease:           t.Version.Prerelease() != "",
+					Annotated:            t.Annotated,
+				})
+			}
+			opts.Out.Report.Data = results
+
+			if asCSV {
+				out, err := tagsCSV(results)
+				if err != nil {
+					return err
+				}
+				opts.Out.Text(out)
+				return nil
+			}
+
+			var table strings.Builder
+			w := tabwriter.NewWriter(&table, 0, 4, 2, ' ', 0)
+			fmt.Fprintln(w, "TAG\tDATE\tAUTHOR\tCOMMIT\tCOMMITS\tTYPE")
+			for _, r := range results {
+				kind := opts.P.Ok("stable")
+				if r.Prerelease {
+					kind = opts.P.Warning("pre-release")
+				}
+				fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%d\t%s\n", r.Tag, r.Date.Format(time.DateOnly), r.Author, shortCommit(r.Commit), r.CommitsSincePrevious, kind)
+			}
+			if err := w.Flush(); err != nil {
+				return err
+			}
+			opts.Out.Text(table.String())
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&constraint, "constraint", "", "only list versions matching a constraint, e.g. '^1.2' or '>= 2.0, < 3'")
+	cmd.Flags().StringVar(&prefix, "prefix", "", "only list tags of a nested module, e.g. 'tools/'")
+	cmd.Flags().BoolVar(&asCSV, "csv", false, "print comma-separated values instead of a table")
+
+	return cmd
+}
+
+// tagsCSV renders the releases as CSV with a header row.
+func tagsCSV(results []tagResult) (string, error) {
+	var out strings.Builder
+	w := csv.NewWriter(&out)
+	_ = w.Write([]string{"tag", "version", "date", "author", "commit", "commits_since_previous", "prerelease", "annotated"})
+	for _, r := range results {
+		_ = w.Write([]string{
+			r.Tag, r.Version, r.Date.Format(time.RFC3339), r.Author, r.Commit,
+			strconv.Itoa(r.CommitsSincePrevious), strconv.FormatBool(r.Prerelease), strconv.FormatBool(r.Annotated),
+		})
+	}
+	w.Flush()
+	return out.String(), w.Error()
+}
+
+// shortCommit abbreviates a commit hash for tables.
+func shortCommit(hash string) string {
+	if len(hash) > 7 {
+		return hash[:7]
+	}
+	return hash
+}
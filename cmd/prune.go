@@ -0,0 +1,128 @@
+package cmd
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/flaticols/bump/internal"
+	"github.com/flaticols/bump/internal/tui"
+	"github.com/spf13/cobra"
+)
+
+// pruneResult is the JSON output of a pre-release tag found by bump prune.
+type pruneResult struct {
+	Tag     string    `json:"tag"`
+	Version string    `json:"version"`
+	Date    time.Time `json:"date"`
+	Commit  string    `json:"commit"`
+	Deleted bool      `json:"deleted"`
+	Remote  bool      `json:"remote"`
+}
+
+func CreatePruneCmd(opts *Options) *cobra.Command {
+	var olderThan string
+	var keepLast int
+
+	cmd := &cobra.Command{
+		Use:   "prune",
+		Short: "Delete pre-release tags superseded by a final release",
+		Long: "Delete the pre-release tags, such as -rc.N, -beta.N or -dev, of versions lower than the latest final release, locally and on the remote.\n" +
+			"The tags to delete are picked in a prompt. Final releases are never deleted.",
+		Example: "  bump prune                       # Pick the superseded pre-release tags to delete\n  bump prune --older-than 30d      # Only tags older than 30 days\n" +
+			"  bump prune --keep-last 2 --brave # Keep the two highest pre-releases of every version, without prompt",
+		Args: cobra.NoArgs,
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			return prepareRun(opts, cmd)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if keepLast < 0 {
+				return withCode(ErrCodeConfig, fmt.Errorf("--keep-last must not be negative"))
+			}
+			var before time.Time
+			if olderThan != "" {
+				age, err := internal.ParseAge(olderThan)
+				if err != nil {
+					return withCode(ErrCodeConfig, err)
+				}
+				before = time.Now().Add(-age)
+			}
+
+			tags, err := internal.ListTags("")
+			if err != nil {
+				return withCode(ErrCodeGit, err)
+			}
+			superseded := internal.SupersededPrereleases(tags, keepLast, before)
+
+			results := make([]pruneResult, 0, len(superseded))
+			opts.Out.Report.Data = &results
+			if len(superseded) == 0 {
+				opts.Out.Ok("no superseded pre-release tags")
+				return nil
+			}
+
+			var localOnly []string
+			if !opts.LocalRepo {
+				diff, err := opts.GitDetailer.CompareRemoteTags()
+				if err != nil {
+					return withCode(ErrCodeGit, err)
+				}
+				for _, t := range diff {
+					if t.State == internal.TagLocalOnly {
+						localOnly = append(localOnly, t.Name)
+					}
+				}
+			}
+
+			choices := make([]tui.Choice, 0, len(superseded))
+			for _, t := range superseded {
+				choices = append(choices, tui.Choice{Label: fmt.Sprintf("%s  %s  %s", t.Name, t.Date.Format(time.DateOnly), shortCommit(t.Commit)), Value: t.Name})
+			}
+			if err := checkPrompt(opts, "deleting tags"); err != nil {
+				return err
+			}
+			selected := tui.AskMultiSelect(fmt.Sprintf("Delete %d superseded pre-release tags?", len(superseded)), choices,
+				tui.SelectAllIf(opts.BraveMode || opts.DryRun))
+
+			for _, t := range superseded {
+				r := pruneResult{Tag: t.Name, Version: t.Version.String(), Date: t.Date, Commit: t.Commit}
+				if !slices.Contains(selected, t.Name) {
+					results = append(results, r)
+					continue
+				}
+
+				if err := opts.GitDetailer.RemoveLocalGitTag(t.Name); err != nil {
+					return withCode(ErrCodeGit, err)
+				}
+				r.Deleted = true
+
+				if !opts.LocalRepo && !slices.Contains(localOnly, t.Name) {
+					pushed := opts.Out.Step(internal.EventPushStarted, internal.EventPushFinished, internal.Event{Name: ":" + t.Name, Tag: t.Name, Remote: internal.DefaultRemote})
+					err := withCode(ErrCodePush, opts.GitDetailer.RemoveRemoteGitTag(t.Name))
+					pushed(err)
+					if err != nil {
+						return err
+					}
+					r.Remote = true
+					if !opts.DryRun && !slices.Contains(opts.Out.Report.RemotesPushed, internal.DefaultRemote) {
+						opts.Out.Report.RemotesPushed = append(opts.Out.Report.RemotesPushed, internal.DefaultRemote)
+					}
+				}
+				if !opts.DryRun {
+					opts.Out.Ok("%s deleted", t.Name)
+				}
+				results = append(results, r)
+			}
+
+			if len(selected) == 0 {
+				opts.Out.Info("no tags selected, nothing deleted")
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&olderThan, "older-than", "", "only delete tags older than the age, e.g. 30d, 2w or 36h")
+	cmd.Flags().IntVar(&keepLast, "keep-last", 0, "keep the N highest pre-releases of every version")
+
+	return cmd
+}
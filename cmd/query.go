@@ -0,0 +1,192 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"text/template"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/flaticols/bump/internal"
+	"github.com/spf13/cobra"
+)
+
+// defaultVersionFormat prints the tag of a version.
+const defaultVersionFormat = "{{.Tag}}"
+
+// versionView is the data of --format templates and the JSON output of the query commands.
+type versionView struct {
+	Part       string `json:"part,omitempty"`
+	Version    string `json:"version"`
+	Tag        string `json:"tag"`
+	Major      uint64 `json:"major"`
+	Minor      uint64 `json:"minor"`
+	Patch      uint64 `json:"patch"`
+	Prerelease string `json:"prerelease,omitempty"`
+	Metadata   string `json:"metadata,omitempty"`
+}
+
+func newVersionView(opts *Options, part semVerPart, ver *semver.Version) versionView {
+	return versionView{
+		Part:       part,
+		Version:    ver.String(),
+		Tag:        opts.P.Version(ver.String()),
+		Major:      ver.Major(),
+		Minor:      ver.Minor(),
+		Patch:      ver.Patch(),
+		Prerelease: ver.Prerelease(),
+		Metadata:   ver.Metadata(),
+	}
+}
+
+func CreateCurrentCmd(opts *Options) *cobra.Command {
+	var format string
+	var fetch bool
+
+	cmd := &cobra.Command{
+		Use:     "current",
+		Short:   "Print the current version",
+		Long:    "Print the version of the latest semver tag without checking or changing the repository",
+		Example: "  bump current                                # v1.2.3\n  bump current --format '{{.Major}}.{{.Minor}}'  # 1.2",
+		Args:    cobra.NoArgs,
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			return prepareRun(opts, cmd)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			tmpl, err := versionTemplate(format)
+			if err != nil {
+				return err
+			}
+
+			if fetch {
+				if err := fetchTags(opts); err != nil {
+					return err
+				}
+			}
+
+			ver, noTags, err := currentVersion(opts)
+			if err != nil {
+				return err
+			}
+			if noTags {
+				return withCode(ErrCodePrecondition, errors.New("no tags found"))
+			}
+
+			view := newVersionView(opts, "", ver)
+			opts.Out.Report.Version = view.Version
+			opts.Out.Report.Tag = view.Tag
+			opts.Out.Report.Data = view
+
+			return printVersions(opts, tmpl, view)
+		},
+	}
+
+	cmd.Flags().StringVar(&format, "format", defaultVersionFormat, "text/template applied to the version, e.g. '{{.Major}}.{{.Minor}}'")
+	cmd.Flags().BoolVar(&fetch, "fetch", false, "fetch the tags of the remote first")
+
+	return cmd
+}
+
+func CreateNextCmd(opts *Options) *cobra.Command {
+	var format string
+	var fetch, all bool
+
+	cmd := &cobra.Command{
+		Use:   "next [major|minor|patch|pre|auto]",
+		Short: "Print the version the next bump would create",
+		Long:  "Print the version that bumping the given part (patch by default) would create, without checking or changing the repository",
+		Example: "  bump next          # v1.2.4\n  bump next minor    # v1.3.0\n  bump next pre      # v1.2.4-rc.0\n" +
+			"  bump next --all    # Prints every candidate\n  bump next --format '{{.Major}}.{{.Minor}}'",
+		Args:      cobra.MatchAll(cobra.MaximumNArgs(1), cobra.OnlyValidArgs),
+		ValidArgs: []string{major, minor, patch, pre, auto},
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			return prepareRun(opts, cmd)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if all && len(args) > 0 {
+				return withCode(ErrCodeConfig, errors.New("--all cannot be combined with a part"))
+			}
+			if all && !cmd.Flags().Changed("format") {
+				format = "{{.Part}}\t" + defaultVersionFormat
+			}
+			tmpl, err := versionTemplate(format)
+			if err != nil {
+				return err
+			}
+
+			if fetch {
+				if err := fetchTags(opts); err != nil {
+					return err
+				}
+			}
+
+			ver, noTags, err := currentVersion(opts)
+			if err != nil {
+				return err
+			}
+			if !noTags {
+				opts.Out.Report.PreviousVersion = ver.String()
+			}
+
+			parts := []semVerPart{getIncPart(args)}
+			if all {
+				parts = []semVerPart{major, minor, patch, pre}
+			}
+
+			views := make([]versionView, 0, len(parts))
+			for _, part := range parts {
+				if part == auto {
+					part, _, err = autoPart(ver, noTags)
+					if err != nil {
+						return err
+					}
+				}
+
+				next, err := createNewVersion(part, ver, opts.PreID)
+				if err != nil {
+					return withCode(ErrCodeConfig, err)
+				}
+				views = append(views, newVersionView(opts, part, next))
+			}
+
+			if all {
+				opts.Out.Report.Data = views
+			} else {
+				opts.Out.Report.Version = views[0].Version
+				opts.Out.Report.Tag = views[0].Tag
+				opts.Out.Report.Data = views[0]
+			}
+
+			return printVersions(opts, tmpl, views...)
+		},
+	}
+
+	cmd.Flags().StringVar(&format, "format", defaultVersionFormat, "text/template applied to the version, e.g. '{{.Major}}.{{.Minor}}'")
+	cmd.Flags().BoolVar(&fetch, "fetch", false, "fetch the tags of the remote first")
+	cmd.Flags().BoolVar(&all, "all", false, "print the next major, minor, patch and pre-release version")
+	cmd.Flags().StringVar(&opts.PreID, "preid", internal.DefaultPreID, "identifier of the first pre-release of a stable version")
+
+	return cmd
+}
+
+// versionTemplate parses a --format template.
+func versionTemplate(format string) (*template.Template, error) {
+	tmpl, err := template.New("format").Option("missingkey=error").Parse(format)
+	if err != nil {
+		return nil, withCode(ErrCodeConfig, fmt.Errorf("invalid format: %w", err))
+	}
+	return tmpl, nil
+}
+
+// printVersions prints every version on its own line through the --format template.
+func printVersions(opts *Options, tmpl *template.Template, views ...versionView) error {
+	var out strings.Builder
+	for _, view := range views {
+		if err := tmpl.Execute(&out, view); err != nil {
+			return withCode(ErrCodeConfig, fmt.Errorf("invalid format: %w", err))
+		}
+		out.WriteString("\n")
+	}
+	opts.Out.Text(out.String())
+	return nil
+}
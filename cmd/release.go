@@ -0,0 +1,211 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/flaticols/bump/internal"
+)
+
+// forgeRelease is a release created on a forge once the tag is pushed.
+type forgeRelease struct {
+	// forge names the API in messages, e.g. GitHub
+	forge      string
+	releaser   internal.Releaser
+	assets     []string
+	draft      bool
+	milestones []string
+	links      []internal.ReleaseLink
+}
+
+// prepareReleases reads the release configuration before anything is tagged, so that a missing token
+// or asset fails the run before the tag is pushed. It returns nothing when no release is configured.
+func prepareReleases(opts *Options) ([]*forgeRelease, error) {
+	if opts.LocalRepo {
+		return nil, nil
+	}
+
+	var releases []*forgeRelease
+	if cfg := opts.Config.Release.GitHub; cfg != nil {
+		rel, err := prepareGitHubRelease(opts, cfg)
+		if err != nil {
+			return nil, err
+		}
+		releases = append(releases, rel)
+	}
+	if cfg := opts.Config.Release.GitLab; cfg != nil {
+		rel, err := prepareGitLabRelease(opts, cfg)
+		if err != nil {
+			return nil, err
+		}
+		releases = append(releases, rel)
+	}
+	return releases, nil
+}
+
+func prepareGitHubRelease(opts *Options, cfg *internal.GitHubReleaseConfig) (*forgeRelease, error) {
+	tokenEnv := cfg.TokenEnv
+	if tokenEnv == "" {
+		tokenEnv = internal.DefaultGitHubTokenEnv
+	}
+	token := os.Getenv(tokenEnv)
+	if token == "" {
+		return nil, withCode(ErrCodeConfig, fmt.Errorf("release.github is configured but %s is not set", tokenEnv))
+	}
+
+	apiURL := cfg.APIURL
+	if apiURL == "" {
+		apiURL = internal.DefaultGitHubAPIURL
+	}
+
+	repository := cfg.Repository
+	if repository == "" {
+		var err error
+		repository, err = remoteRepository("release.github.repository")
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	assets, err := internal.ExpandAssets(cfg.Assets)
+	if err != nil {
+		return nil, withCode(ErrCodeConfig, err)
+	}
+
+	return &forgeRelease{
+		forge:    "GitHub",
+		releaser: &internal.GitHubReleaser{APIURL: apiURL, Repository: repository, Token: token, OnRetry: retryWarning(opts, "GitHub")},
+		assets:   assets,
+		draft:    cfg.Draft,
+	}, nil
+}
+
+func prepareGitLabRelease(opts *Options, cfg *internal.GitLabReleaseConfig) (*forgeRelease, error) {
+	tokenEnv := cfg.TokenEnv
+	if tokenEnv == "" {
+		tokenEnv = internal.DefaultGitLabTokenEnv
+	}
+	token, jobToken := os.Getenv(tokenEnv), false
+	if token == "" {
+		token, jobToken = os.Getenv("CI_JOB_TOKEN"), true
+	}
+	if token == "" {
+		return nil, withCode(ErrCodeConfig, fmt.Errorf("release.gitlab is configured but neither %s nor CI_JOB_TOKEN is set", tokenEnv))
+	}
+
+	apiURL := cfg.APIURL
+	if apiURL == "" {
+		apiURL = os.Getenv("CI_API_V4_URL")
+	}
+	if apiURL == "" {
+		apiURL = internal.DefaultGitLabAPIURL
+	}
+
+	projectID := cfg.ProjectID
+	if projectID == "" {
+		projectID = os.Getenv("CI_PROJECT_ID")
+	}
+	if projectID == "" {
+		var err error
+		projectID, err = remoteRepository("release.gitlab.project-id")
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for _, link := range cfg.Links {
+		if link.Name == "" || link.URL == "" {
+			return nil, withCode(ErrCodeConfig, errors.New("every link in release.gitlab.links needs a name and a url"))
+		}
+	}
+
+	return &forgeRelease{
+		forge: "GitLab",
+		releaser: &internal.GitLabReleaser{
+			APIURL:    apiURL,
+			ProjectID: projectID,
+			Token:     token,
+			JobToken:  jobToken,
+			OnRetry:   retryWarning(opts, "GitLab"),
+		},
+		milestones: cfg.Milestones,
+		links:      cfg.Links,
+	}, nil
+}
+
+// retryWarning reports the failed attempts of a request to the forge that are retried.
+func retryWarning(opts *Options, forge string) func(attempt int, err error) {
+	return func(attempt int, err error) {
+		opts.Out.Warning("%s release attempt %d failed, retrying: %s", forge, attempt, err)
+	}
+}
+
+// remoteRepository reads the path of the repository from the origin remote, the setting named by key overrides it.
+func remoteRepository(key string) (string, error) {
+	remote, err := internal.RemoteURL()
+	if err != nil {
+		return "", withCode(ErrCodeGit, err)
+	}
+	repository, err := internal.RepositoryPath(remote)
+	if err != nil {
+		return "", withCode(ErrCodeConfig, fmt.Errorf("%w, set %s", err, key))
+	}
+	return repository, nil
+}
+
+// createReleases creates the releases of the pushed tag with the generated notes as their body.
+// The tag is kept when a release fails, it can be created by hand from the pushed tag.
+func createReleases(opts *Options, releases []*forgeRelease, ver *semver.Version, env internal.HookEnv, previousTag string) error {
+	if len(releases) == 0 {
+		return nil
+	}
+	tag := env.Tag
+
+	if opts.DryRun {
+		for _, rel := range releases {
+			description := fmt.Sprintf("%s release %s", rel.forge, tag)
+			if len(rel.assets) > 0 {
+				description += " with " + strings.Join(rel.assets, ", ")
+			}
+			opts.Out.Plan(PlannedAction{Action: ActionRelease, Description: description})
+		}
+		return nil
+	}
+
+	notes, err := releaseNotes(ver, tag, previousTag)
+	if err != nil {
+		return withCode(ErrCodeGit, err)
+	}
+
+	for _, rel := range releases {
+		links := make([]internal.ReleaseLink, len(rel.links))
+		for i, link := range rel.links {
+			link.URL = env.Expand(link.URL)
+			links[i] = link
+		}
+
+		done := opts.Out.Step(internal.EventReleaseStarted, internal.EventReleaseFinished, internal.Event{Name: rel.forge, Tag: tag})
+		url, err := rel.releaser.CreateRelease(context.Background(), internal.ReleaseRequest{
+			Tag:        tag,
+			Name:       tag,
+			Body:       notes,
+			Prerelease: ver.Prerelease() != "",
+			Draft:      rel.draft,
+			Assets:     rel.assets,
+			Milestones: rel.milestones,
+			Links:      links,
+		})
+		done(err)
+		if err != nil {
+			return withCode(ErrCodeRelease, fmt.Errorf("tag %s is pushed but its %s release failed: %w", tag, rel.forge, err))
+		}
+
+		opts.Out.Report.Releases = append(opts.Out.Report.Releases, url)
+		opts.Out.Ok("%s release created: %s", rel.forge, url)
+	}
+	return nil
+}
@@ -0,0 +1,236 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/flaticols/bump/internal"
+)
+
+// Output formats selected with --output.
+const (
+	OutputText = "text"
+	OutputJSON = "json"
+)
+
+// CheckStatus is the outcome of a single preflight check.
+type CheckStatus string
+
+const (
+	CheckOk      CheckStatus = "ok"
+	CheckWarning CheckStatus = "warning"
+	CheckFailed  CheckStatus = "failed"
+)
+
+// CheckResult is the outcome of a preflight check as reported in JSON output.
+type CheckResult struct {
+	Name    string      `json:"name"`
+	Status  CheckStatus `json:"status"`
+	Message string      `json:"message"`
+}
+
+// Message is a progress line as reported in JSON output.
+type Message struct {
+	Level   string `json:"level"`
+	Message string `json:"message"`
+}
+
+// ErrorReport describes the failure of a run in JSON output.
+type ErrorReport struct {
+	Code    ErrorCode `json:"code"`
+	Message string    `json:"message"`
+}
+
+// Report is the single document printed at the end of a run with --output json.
+type Report struct {
+	Command         string          `json:"command"`
+	Success         bool            `json:"success"`
+	Checks          []CheckResult   `json:"checks"`
+	Messages        []Message       `json:"messages"`
+	PreviousVersion string          `json:"previous_version,omitempty"`
+	Version         string          `json:"version,omitempty"`
+	Tag             string          `json:"tag,omitempty"`
+	Commit          string          `json:"commit,omitempty"`
+	Files           []string        `json:"files,omitempty"`
+	RemotesPushed   []string        `json:"remotes_pushed"`
+	Releases        []string        `json:"releases,omitempty"`
+	DryRun          bool            `json:"dry_run,omitempty"`
+	Plan            []PlannedAction `json:"plan,omitempty"`
+	Data            any             `json:"data,omitempty"`
+	Error           *ErrorReport    `json:"error,omitempty"`
+}
+
+// Reporter prints the progress of a command as colored text lines, or collects it into a Report
+// which is printed as JSON once the command is finished. With an event log it also streams
+// the steps of the command as they happen.
+type Reporter struct {
+	Report Report
+
+	format   string
+	p        TextPrinters
+	out      io.Writer
+	finished bool
+
+	events  *internal.EventLog
+	runDone func(internal.Event)
+	checks  map[string]func(internal.Event)
+}
+
+// NewReporter creates a reporter for the given output format writing to out.
+func NewReporter(format string, p TextPrinters, out io.Writer) *Reporter {
+	return &Reporter{
+		Report:  Report{Checks: []CheckResult{}, Messages: []Message{}, RemotesPushed: []string{}},
+		format:  format,
+		p:       p,
+		out:     out,
+		runDone: func(internal.Event) {},
+		checks:  make(map[string]func(internal.Event)),
+	}
+}
+
+// StartEvents streams the steps of the command to the event log, beginning with the run_started event.
+func (r *Reporter) StartEvents(log *internal.EventLog) {
+	r.events = log
+	r.runDone = log.Start(internal.EventRunStarted, internal.EventRunFinished, internal.Event{Command: r.Report.Command})
+}
+
+// Event writes a single event to the event log, if there is one.
+func (r *Reporter) Event(e internal.Event) {
+	r.events.Emit(e)
+}
+
+// Step emits the started event of a step and returns a function that emits its finished event
+// with the outcome of the step.
+func (r *Reporter) Step(started, finished internal.EventType, e internal.Event) func(err error) {
+	done := r.events.Start(started, finished, e)
+	return func(err error) {
+		done(eventResult(err))
+	}
+}
+
+// BeginCheck marks the start of a preflight check, the check is finished by Check.
+func (r *Reporter) BeginCheck(name string) {
+	r.checks[name] = r.events.Start(internal.EventCheckStarted, internal.EventCheckFinished, internal.Event{Name: name})
+}
+
+// JSON reports whether the output is collected into a JSON document.
+func (r *Reporter) JSON() bool {
+	return r.format == OutputJSON
+}
+
+// ToolOutput returns where the output of external tools such as hooks should go,
+// so that it does not corrupt the JSON document on stdout.
+func (r *Reporter) ToolOutput() io.Writer {
+	if r.JSON() {
+		return os.Stderr
+	}
+	return r.out
+}
+
+func (r *Reporter) Ok(format string, a ...any) {
+	r.line("ok", r.p.Symbols.Ok, format, a...)
+}
+
+func (r *Reporter) Info(format string, a ...any) {
+	r.line("info", r.p.Symbols.Bullet, format, a...)
+}
+
+func (r *Reporter) Warning(format string, a ...any) {
+	r.line("warning", r.p.Symbols.Warning, format, a...)
+}
+
+func (r *Reporter) Error(format string, a ...any) {
+	r.line("error", r.p.Symbols.Error, format, a...)
+}
+
+// Fatal prints an error message without a symbol, the way bump reports errors it cannot continue after.
+func (r *Reporter) Fatal(msg string) {
+	r.Report.Messages = append(r.Report.Messages, Message{Level: "error", Message: msg})
+	if !r.JSON() {
+		fmt.Fprintln(r.out, r.p.Err("%s", msg))
+	}
+}
+
+// Text prints raw text, such as tables or generated documents, in text mode only.
+func (r *Reporter) Text(s string) {
+	if !r.JSON() {
+		fmt.Fprint(r.out, s)
+	}
+}
+
+// Plan records an action skipped because of --dry-run and prints what would have been done.
+func (r *Reporter) Plan(a PlannedAction) {
+	r.Report.Plan = append(r.Report.Plan, a)
+
+	switch {
+	case a.Action == ActionHook:
+		r.Info("would run %s hook: %s", a.Description, a.Command)
+	case a.Action == ActionRelease:
+		r.Info("would create %s", a.Description)
+	case a.Command != "":
+		r.Info("would run: %s", a.Command)
+	default:
+		r.Info("would edit %s (%s)", a.Path, a.Description)
+	}
+}
+
+// Check records the result of a preflight check and prints it with the matching symbol.
+func (r *Reporter) Check(name string, status CheckStatus, format string, a ...any) {
+	msg := fmt.Sprintf(format, a...)
+	r.Report.Checks = append(r.Report.Checks, CheckResult{Name: name, Status: status, Message: msg})
+	if _, ok := r.checks[name]; !ok {
+		r.BeginCheck(name)
+	}
+	r.checks[name](internal.Event{Status: string(status), Message: msg})
+	delete(r.checks, name)
+
+	switch status {
+	case CheckOk:
+		r.Ok("%s", msg)
+	case CheckWarning:
+		r.Warning("%s", msg)
+	default:
+		r.Error("%s", msg)
+	}
+}
+
+func (r *Reporter) line(level, symbol, format string, a ...any) {
+	msg := fmt.Sprintf(format, a...)
+	r.Report.Messages = append(r.Report.Messages, Message{Level: level, Message: msg})
+	if !r.JSON() {
+		fmt.Fprintf(r.out, "%s %s\n", symbol, msg)
+	}
+}
+
+// Finish completes the report with the outcome of the run, prints it in JSON mode and closes the event log.
+// It is safe to call on a nil reporter and prints the document only once.
+func (r *Reporter) Finish(err error) {
+	if r == nil || r.finished {
+		return
+	}
+	r.finished = true
+
+	r.Report.Success = err == nil
+	if err != nil {
+		r.Report.Error = &ErrorReport{Code: errorCode(err), Message: err.Error()}
+	}
+
+	if r.JSON() {
+		enc := json.NewEncoder(r.out)
+		enc.SetIndent("", "  ")
+		_ = enc.Encode(r.Report)
+	}
+
+	r.runDone(eventResult(err))
+	_ = r.events.Close()
+}
+
+// eventResult describes the outcome of a step in a finished event.
+func eventResult(err error) internal.Event {
+	if err != nil {
+		return internal.Event{Status: string(CheckFailed), Message: err.Error(), ErrorCode: string(errorCode(err))}
+	}
+	return internal.Event{Status: string(CheckOk)}
+}
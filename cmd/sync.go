@@ -0,0 +1,198 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/flaticols/bump/internal"
+	"github.com/flaticols/bump/internal/tui"
+	"github.com/spf13/cobra"
+)
+
+// Sides kept for diverged tags, selected with --prefer or interactively.
+const (
+	preferLocal  = "local"
+	preferRemote = "remote"
+	preferSkip   = "skip"
+)
+
+// Actions taken by bump sync for a tag.
+const (
+	syncPushed  = "pushed"
+	syncFetched = "fetched"
+	syncSkipped = "skipped"
+)
+
+// syncResult is the JSON output of a tag reconciled by bump sync.
+type syncResult struct {
+	Tag    string `json:"tag"`
+	State  string `json:"state"`
+	Local  string `json:"local,omitempty"`
+	Remote string `json:"remote,omitempty"`
+	Action string `json:"action,omitempty"`
+}
+
+func CreateSyncCmd(opts *Options) *cobra.Command {
+	var prefer string
+	var check bool
+
+	cmd := &cobra.Command{
+		Use:   "sync",
+		Short: "Reconcile the local and remote tags",
+		Long: "Compare the local tags with the tags of the remote, including tags that point to different commits on both sides.\n" +
+			"Local-only tags are pushed, remote-only tags are fetched and for diverged tags you choose which side to keep.",
+		Example: "  bump sync                  # Reconcile the tags interactively\n  bump sync --check          # Only report the differences, fail when there are some\n" +
+			"  bump sync --brave --prefer remote  # Push and fetch without prompts, take the remote side of diverged tags",
+		Args: cobra.NoArgs,
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			return prepareRun(opts, cmd)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if prefer != "" && prefer != preferLocal && prefer != preferRemote && prefer != preferSkip {
+				return withCode(ErrCodeConfig, fmt.Errorf("unknown side '%s', use %s, %s or %s", prefer, preferLocal, preferRemote, preferSkip))
+			}
+			if opts.LocalRepo {
+				return withCode(ErrCodeConfig, errors.New("sync needs a remote, it cannot run with --local"))
+			}
+
+			tags, err := opts.GitDetailer.CompareRemoteTags()
+			if err != nil {
+				return withCode(ErrCodeGit, err)
+			}
+
+			results := make([]syncResult, 0, len(tags))
+			opts.Out.Report.Data = &results
+			if len(tags) == 0 {
+				opts.Out.Ok("local and %s tags are in sync", internal.DefaultRemote)
+				return nil
+			}
+
+			// every difference is listed before anything is pushed or fetched
+			for _, t := range tags {
+				if check {
+					opts.Out.Warning("%s", describeTagSync(t))
+				} else {
+					opts.Out.Info("%s", describeTagSync(t))
+				}
+			}
+			if check {
+				for _, t := range tags {
+					results = append(results, syncResult{Tag: t.Name, State: string(t.State), Local: t.Local, Remote: t.Remote})
+				}
+				return reported(withCode(ErrCodePrecondition, fmt.Errorf("%d tags differ from %s", len(tags), internal.DefaultRemote)))
+			}
+			if local := tagsInState(tags, internal.TagLocalOnly); opts.BraveMode && len(local) > 0 {
+				opts.Out.Warning("pushing %d local-only tags to %s without asking: %s", len(local), internal.DefaultRemote, strings.Join(local, ", "))
+			}
+
+			for _, t := range tags {
+				r := syncResult{Tag: t.Name, State: string(t.State), Local: t.Local, Remote: t.Remote}
+				r.Action, err = syncTag(opts, t, prefer)
+				if err != nil {
+					return err
+				}
+				results = append(results, r)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&prefer, "prefer", "", "side kept for diverged tags without asking: local, remote or skip")
+	cmd.Flags().BoolVar(&check, "check", false, "only report the differences and exit with code 3 when there are some")
+
+	return cmd
+}
+
+// syncTag pushes, fetches or skips a tag, asking first unless in brave or dry-run mode.
+func syncTag(opts *Options, t internal.TagSync, prefer string) (string, error) {
+	unattended := opts.BraveMode || opts.DryRun
+	if t.State != internal.TagDiverged || prefer == "" {
+		if err := checkPrompt(opts, "syncing "+t.Name); err != nil {
+			return "", err
+		}
+	}
+
+	side := preferSkip
+	switch t.State {
+	case internal.TagLocalOnly:
+		if tui.AskConfirmation(fmt.Sprintf("Push %s to %s?", t.Name, internal.DefaultRemote), tui.AvoidIf(unattended, true)) {
+			side = preferLocal
+		}
+	case internal.TagRemoteOnly:
+		if tui.AskConfirmation(fmt.Sprintf("Fetch %s from %s?", t.Name, internal.DefaultRemote), tui.AvoidIf(unattended, true)) {
+			side = preferRemote
+		}
+	case internal.TagDiverged:
+		side = tui.AskChoice(fmt.Sprintf("Which %s do you keep?", t.Name), []tui.Choice{
+			{Label: fmt.Sprintf("Local %s, force push it to %s", shortCommit(t.Local), internal.DefaultRemote), Value: preferLocal},
+			{Label: fmt.Sprintf("Remote %s, overwrite the local tag", shortCommit(t.Remote)), Value: preferRemote},
+			{Label: "Skip", Value: preferSkip},
+		}, tui.ChooseIf(unattended || prefer != "", prefer))
+	}
+
+	switch side {
+	case preferLocal:
+		pushed := opts.Out.Step(internal.EventPushStarted, internal.EventPushFinished, internal.Event{Name: t.Name, Tag: t.Name, Remote: internal.DefaultRemote})
+		var err error
+		if t.State == internal.TagDiverged {
+			err = opts.GitDetailer.ForcePushGitTag(t.Name)
+		} else {
+			err = opts.GitDetailer.PushGitTag(t.Name)
+		}
+		err = withCode(ErrCodePush, err)
+		pushed(err)
+		if err != nil {
+			return "", err
+		}
+		if !opts.DryRun {
+			opts.Out.Ok("%s pushed to %s", t.Name, internal.DefaultRemote)
+			if !slices.Contains(opts.Out.Report.RemotesPushed, internal.DefaultRemote) {
+				opts.Out.Report.RemotesPushed = append(opts.Out.Report.RemotesPushed, internal.DefaultRemote)
+			}
+		}
+		return syncPushed, nil
+	case preferRemote:
+		fetched := opts.Out.Step(internal.EventFetchStarted, internal.EventFetchFinished, internal.Event{Name: t.Name, Tag: t.Name, Remote: internal.DefaultRemote})
+		err := withCode(ErrCodeGit, opts.GitDetailer.FetchGitTag(t.Name, t.State == internal.TagDiverged))
+		fetched(err)
+		if err != nil {
+			return "", err
+		}
+		if !opts.DryRun {
+			opts.Out.Ok("%s fetched from %s", t.Name, internal.DefaultRemote)
+		}
+		return syncFetched, nil
+	default:
+		if t.State == internal.TagDiverged && unattended && prefer == "" {
+			opts.Out.Warning("%s skipped, use --prefer to choose a side", t.Name)
+		} else {
+			opts.Out.Warning("%s skipped", t.Name)
+		}
+		return syncSkipped, nil
+	}
+}
+
+// describeTagSync explains how a tag differs between the local repository and the remote.
+func describeTagSync(t internal.TagSync) string {
+	switch t.State {
+	case internal.TagLocalOnly:
+		return fmt.Sprintf("%s (%s) exists only locally", t.Name, shortCommit(t.Local))
+	case internal.TagRemoteOnly:
+		return fmt.Sprintf("%s (%s) exists only on %s", t.Name, shortCommit(t.Remote), internal.DefaultRemote)
+	default:
+		return fmt.Sprintf("%s points to %s locally and to %s on %s", t.Name, shortCommit(t.Local), shortCommit(t.Remote), internal.DefaultRemote)
+	}
+}
+
+// tagsInState returns the names of the tags in the given state.
+func tagsInState(tags []internal.TagSync, state internal.TagSyncState) []string {
+	var names []string
+	for _, t := range tags {
+		if t.State == state {
+			names = append(names, t.Name)
+		}
+	}
+	return names
+}
@@ -3,7 +3,6 @@ package cmd
 import (
 	"errors"
 	"fmt"
-	"os"
 
 	"github.com/flaticols/bump/internal"
 	"github.com/flaticols/bump/internal/tui"
@@ -23,34 +22,64 @@ func CreateUndoCmd(opts *Options) *cobra.Command {
 			if err != nil {
 				if errors.As(err, &tagErr) {
 					if tagErr.NoTags {
-						fmt.Printf("%s no tags found to remove\n", opts.P.Symbols.Error)
-						opts.Exit()
+						return withCode(ErrCodePrecondition, errors.New("no tags found to remove"))
 					}
-					fmt.Println(opts.P.Err("tag '%s' is not a valid semver tag", tagErr.Tag))
-					os.Exit(1)
+					return withCode(ErrCodeInvalidTag, fmt.Errorf("tag '%s' is not a valid semver tag", tagErr.Tag))
 				}
-				return err
+				return withCode(ErrCodeGit, err)
 			}
 
 			tag := opts.P.Version(ver.String())
-			confirm := tui.AskConfirmation("Are you sure?", tui.Yes(fmt.Sprintf("Yes remove %s!", tag)), tui.AvoidIf(opts.BraveMode, true))
+			opts.Out.Report.PreviousVersion = ver.String()
+			opts.Out.Report.Tag = tag
+			if err := checkPrompt(opts, "removing tag "+tag); err != nil {
+				return err
+			}
+			confirm := tui.AskConfirmation("Are you sure?", tui.Yes(fmt.Sprintf("Yes remove %s!", tag)), tui.AvoidIf(opts.BraveMode || opts.DryRun, true))
+
+			if !confirm {
+				return withCode(ErrCodeAborted, fmt.Errorf("aborted, tag %s kept", tag))
+			}
 
-			if confirm {
-				fmt.Printf("%s removing tag %s\n", opts.P.Symbols.Bullet, opts.P.Info(tag))
-				if err := opts.GitDetailer.RemoveLocalGitTag(tag); err != nil {
-					return err
+			opts.Out.Info("removing tag %s", opts.P.Info(tag))
+			if err := opts.GitDetailer.RemoveLocalGitTag(tag); err != nil {
+				return withCode(ErrCodeGit, err)
+			}
+			if !opts.DryRun {
+				opts.Out.Ok("local tag removed")
+			}
+			if !opts.LocalRepo {
+				pushed := opts.Out.Step(internal.EventPushStarted, internal.EventPushFinished, internal.Event{Name: ":" + tag, Tag: tag, Remote: internal.DefaultRemote})
+				err := withCode(ErrCodePush, opts.GitDetailer.RemoveRemoteGitTag(tag))
+				pushed(err)
+				if err != nil {
+					opts.Out.Error("remote tag not removed")
+					opts.Out.Error("error: %s", err.Error())
+					return reported(err)
 				}
-				fmt.Printf("%s local tag removed\n", opts.P.Symbols.Ok)
-				if !opts.LocalRepo {
-					if err := opts.GitDetailer.RemoveRemoteGitTag(tag); err != nil {
-						fmt.Printf("%s remote tag not removed\n", opts.P.Symbols.Error)
-						fmt.Printf("%s error: %s\n", opts.P.Symbols.Error, err.Error())
-						os.Exit(1)
-					}
-					fmt.Printf("%s remote tag removed\n", opts.P.Symbols.Ok)
+				if !opts.DryRun {
+					opts.Out.Ok("remote tag removed")
+					opts.Out.Report.RemotesPushed = append(opts.Out.Report.RemotesPushed, internal.DefaultRemote)
 				}
 			}
 
+			hookEnv := internal.HookEnv{PreviousVersion: ver.String(), Tag: tag}
+			if !opts.LocalRepo {
+				hookEnv.Remote = internal.DefaultRemote
+			}
+			if cur, err := opts.GitDetailer.GetCurrentVersion(); err == nil {
+				hookEnv.NewVersion = cur.String()
+				opts.Out.Report.Version = cur.String()
+			}
+			hookEnv.Commit, err = opts.GitDetailer.GetHeadCommit()
+			if err != nil {
+				return withCode(ErrCodeGit, err)
+			}
+
+			if err := runPostHooks(opts, internal.PostUndo, hookEnv, nil); err != nil {
+				return err
+			}
+
 			return nil
 		},
 	}
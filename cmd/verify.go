@@ -0,0 +1,107 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/flaticols/bump/internal"
+	"github.com/spf13/cobra"
+)
+
+// problemResult is the JSON output of a problem found by bump verify.
+type problemResult struct {
+	Check   string `json:"check"`
+	Tag     string `json:"tag"`
+	Message string `json:"message"`
+}
+
+func CreateVerifyCmd(opts *Options) *cobra.Command {
+	var branch string
+	var requireAnnotated bool
+
+	cmd := &cobra.Command{
+		Use:   "verify",
+		Short: "Verify the consistency of the tag history",
+		Long: "Verify the semver tags for version gaps, duplicate versions, versions ordered differently than the commit history, " +
+			"tags outside the default branch, tags named like a branch and, when required, lightweight tags.\n" +
+			"Exits with a non-zero code when a problem is found so it can gate a CI pipeline.",
+		Example: "  bump verify                      # Check the tags against the default branch\n  bump verify --branch release     # Check the tags against another branch\n" +
+			"  bump verify --require-annotated  # Also fail on lightweight tags",
+		Args: cobra.NoArgs,
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			return prepareHistory(opts, cmd)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			tags, err := internal.ListTags("")
+			if err != nil {
+				return withCode(ErrCodeGit, err)
+			}
+
+			var problems []internal.TagProblem
+			report := func(name, subject string, found []internal.TagProblem) {
+				problems = append(problems, found...)
+				if len(found) == 0 {
+					opts.Out.Check(name, CheckOk, "no %s", subject)
+					return
+				}
+				opts.Out.Check(name, CheckFailed, "%d %s", len(found), subject)
+				for _, p := range found {
+					opts.Out.Error("  %s", p.Message)
+				}
+			}
+
+			report(internal.TagCheckGaps, "version gaps", internal.VersionGaps(tags))
+			report(internal.TagCheckDuplicates, "duplicate versions", internal.DuplicateVersions(tags))
+
+			opts.Out.BeginCheck(internal.TagCheckOrder)
+			found, err := internal.AncestryOrder(tags, internal.IsAncestor)
+			if err != nil {
+				opts.Out.Check(internal.TagCheckOrder, CheckFailed, "%v", err)
+				return reported(withCode(ErrCodeGit, err))
+			}
+			report(internal.TagCheckOrder, "versions out of commit order", found)
+
+			opts.Out.BeginCheck(internal.TagCheckBranch)
+			if branch == "" {
+				branch, err = internal.DefaultBranch(opts.Config.Branches.Default)
+			}
+			if err != nil {
+				opts.Out.Check(internal.TagCheckBranch, CheckWarning, "%v, use --branch to name it", err)
+			} else {
+				found, err = internal.OutsideBranch(tags, internal.BranchRef(branch), internal.IsAncestor)
+				if err != nil {
+					opts.Out.Check(internal.TagCheckBranch, CheckFailed, "%v", err)
+					return reported(withCode(ErrCodeGit, err))
+				}
+				report(internal.TagCheckBranch, "tags outside "+branch, found)
+			}
+
+			opts.Out.BeginCheck(internal.TagCheckClashes)
+			branches, err := internal.BranchNames()
+			if err != nil {
+				opts.Out.Check(internal.TagCheckClashes, CheckFailed, "%v", err)
+				return reported(withCode(ErrCodeGit, err))
+			}
+			report(internal.TagCheckClashes, "tags named like a branch", internal.BranchClashes(tags, branches))
+
+			if requireAnnotated || opts.Config.Tags.Annotated {
+				report(internal.TagCheckLightweight, "lightweight tags", internal.LightweightTags(tags))
+			}
+
+			results := make([]problemResult, 0, len(problems))
+			for _, p := range problems {
+				results = append(results, problemResult{Check: p.Check, Tag: p.Tag, Message: p.Message})
+			}
+			opts.Out.Report.Data = results
+
+			if len(problems) > 0 {
+				return reported(withCode(ErrCodePrecondition, fmt.Errorf("%d problems found in %d tags", len(problems), len(tags))))
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&branch, "branch", "", "branch every tag must be reachable from (default: the default branch)")
+	cmd.Flags().BoolVar(&requireAnnotated, "require-annotated", false, "fail on lightweight tags (also enabled by tags.annotated in the config)")
+
+	return cmd
+}
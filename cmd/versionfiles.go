@@ -0,0 +1,33 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/Masterminds/semver/v3"
+)
+
+// updateVersionFiles writes the new version into every configured version file and returns the files that changed.
+func updateVersionFiles(opts *Options, ver *semver.Version) ([]string, error) {
+	var updated []string
+
+	for _, f := range opts.Config.VersionFiles {
+		data, changed, err := f.Render(ver.String())
+		if err != nil {
+			return nil, withCode(ErrCodeConfig, fmt.Errorf("failed to update version file: %w", err))
+		}
+
+		if !changed {
+			opts.Out.Info("%s already at %s", f.Path, ver.String())
+			continue
+		}
+		if err := writeFile(opts, f.Path, data, "set version "+ver.String()); err != nil {
+			return nil, withCode(ErrCodeConfig, fmt.Errorf("failed to update version file: %w", err))
+		}
+		if !opts.DryRun {
+			opts.Out.Ok("%s updated to %s", f.Path, ver.String())
+		}
+		updated = append(updated, f.Path)
+	}
+
+	return updated, nil
+}
@@ -0,0 +1,292 @@
+package internal
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go/importer"
+	"go/token"
+	"go/types"
+	"io"
+	"os"
+	"os/exec"
+	"slices"
+	"strings"
+)
+
+// APIChangeKind classifies a change of the exported API by its semver impact.
+type APIChangeKind int
+
+const (
+	APINone APIChangeKind = iota
+	APICompatible
+	APIIncompatible
+)
+
+func (k APIChangeKind) String() string {
+	switch k {
+	case APICompatible:
+		return "compatible"
+	case APIIncompatible:
+		return "incompatible"
+	default:
+		return "none"
+	}
+}
+
+// APIChange is a single difference between two versions of the exported API.
+type APIChange struct {
+	Package string
+	Name    string
+	Kind    APIChangeKind
+	Message string
+}
+
+func (c APIChange) String() string {
+	if c.Name == "" {
+		return fmt.Sprintf("%s: %s", c.Package, c.Message)
+	}
+	return fmt.Sprintf("%s: %s %s", c.Package, c.Name, c.Message)
+}
+
+// APIDiff is the list of differences between two versions of the exported API.
+type APIDiff struct {
+	Changes []APIChange
+}
+
+// Kind returns the most severe kind of change in the diff.
+func (d APIDiff) Kind() APIChangeKind {
+	kind := APINone
+	for _, c := range d.Changes {
+		kind = max(kind, c.Kind)
+	}
+	return kind
+}
+
+// apiFeature is a single element of a package API, such as a function, a struct field or a method.
+type apiFeature struct {
+	desc string
+	// addBreaks is set for features whose addition is incompatible, such as methods of interfaces
+	addBreaks bool
+}
+
+// GoAPI maps package import paths to their exported features.
+type GoAPI map[string]map[string]apiFeature
+
+// DiffGoAPI compares the exported API of the Go module at the given git ref with the working tree.
+// The ref is checked out into a temporary worktree which is removed afterwards.
+func DiffGoAPI(ref string) (*APIDiff, error) {
+	dir, err := os.MkdirTemp("", "bump-api-")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temporary directory: %w", err)
+	}
+	defer os.RemoveAll(dir)
+
+	output, err := exec.Command("git", "worktree", "add", "--detach", dir, ref).CombinedOutput()
+	if err != nil {
+		return nil, fmt.Errorf("failed to check out %s: %v - %s", ref, err, string(output))
+	}
+	defer exec.Command("git", "worktree", "remove", "--force", dir).Run()
+
+	oldAPI, err := LoadGoAPI(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load API of %s: %w", ref, err)
+	}
+
+	newAPI, err := LoadGoAPI(".")
+	if err != nil {
+		return nil, fmt.Errorf("failed to load API of working tree: %w", err)
+	}
+
+	diff := CompareGoAPI(oldAPI, newAPI)
+	return &diff, nil
+}
+
+type goListPackage struct {
+	ImportPath string
+	Name       string
+	Export     string
+	DepOnly    bool
+	Error      *struct {
+		Err string
+	}
+}
+
+// LoadGoAPI type-checks every public package of the Go module in dir and collects its exported API.
+// Main packages and internal packages are not part of the public API and are skipped.
+func LoadGoAPI(dir string) (GoAPI, error) {
+	cmd := exec.Command("go", "list", "-e", "-deps", "-export", "-json=ImportPath,Name,Export,DepOnly,Error", "./...")
+	cmd.Dir = dir
+	cmd.Stderr = io.Discard
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("go list failed: %w", err)
+	}
+
+	exports := make(map[string]string)
+	var public []string
+
+	dec := json.NewDecoder(strings.NewReader(string(output)))
+	for {
+		var p goListPackage
+		if err := dec.Decode(&p); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return nil, fmt.Errorf("failed to parse go list output: %w", err)
+		}
+
+		if p.Error != nil {
+			return nil, fmt.Errorf("package %s: %s", p.ImportPath, p.Error.Err)
+		}
+
+		exports[p.ImportPath] = p.Export
+		if !p.DepOnly && p.Name != "main" && !isInternalPackage(p.ImportPath) {
+			public = append(public, p.ImportPath)
+		}
+	}
+
+	fset := token.NewFileSet()
+	imp := importer.ForCompiler(fset, "gc", func(path string) (io.ReadCloser, error) {
+		file, ok := exports[path]
+		if !ok || file == "" {
+			return nil, fmt.Errorf("no export data for %s", path)
+		}
+		return os.Open(file)
+	})
+
+	api := make(GoAPI)
+	for _, path := range public {
+		pkg, err := imp.Import(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to type-check %s: %w", path, err)
+		}
+		api[path] = packageFeatures(pkg)
+	}
+
+	return api, nil
+}
+
+func isInternalPackage(path string) bool {
+	return slices.Contains(strings.Split(path, "/"), "internal")
+}
+
+// packageFeatures lists the exported features of a type-checked package.
+func packageFeatures(pkg *types.Package) map[string]apiFeature {
+	features := make(map[string]apiFeature)
+	scope := pkg.Scope()
+	qf := types.RelativeTo(pkg)
+
+	for _, name := range scope.Names() {
+		obj := scope.Lookup(name)
+		if !obj.Exported() {
+			continue
+		}
+
+		tn, ok := obj.(*types.TypeName)
+		if !ok {
+			features[name] = apiFeature{desc: types.ObjectString(obj, qf)}
+			continue
+		}
+
+		if tn.IsAlias() {
+			features[name] = apiFeature{desc: "type " + name + " = " + types.TypeString(types.Unalias(tn.Type()), qf)}
+			continue
+		}
+
+		named, ok := tn.Type().(*types.Named)
+		if !ok {
+			continue
+		}
+
+		switch u := named.Underlying().(type) {
+		case *types.Struct:
+			features[name] = apiFeature{desc: "type " + types.TypeString(named, qf) + " struct"}
+			for i := 0; i < u.NumFields(); i++ {
+				if f := u.Field(i); f.Exported() {
+					features[name+"."+f.Name()] = apiFeature{desc: "field " + types.TypeString(f.Type(), qf)}
+				}
+			}
+		case *types.Interface:
+			features[name] = apiFeature{desc: "type " + types.TypeString(named, qf) + " interface"}
+			// Interfaces with unexported methods cannot be implemented outside the package,
+			// so adding methods to them does not break anybody.
+			sealed := false
+			for i := 0; i < u.NumMethods(); i++ {
+				if !u.Method(i).Exported() {
+					sealed = true
+				}
+			}
+			for i := 0; i < u.NumMethods(); i++ {
+				if m := u.Method(i); m.Exported() {
+					features[name+"."+m.Name()] = apiFeature{desc: types.TypeString(m.Type(), qf), addBreaks: !sealed}
+				}
+			}
+			continue
+		default:
+			features[name] = apiFeature{desc: "type " + types.TypeString(named, qf) + " " + types.TypeString(u, qf)}
+		}
+
+		mset := types.NewMethodSet(types.NewPointer(named))
+		for i := 0; i < mset.Len(); i++ {
+			if m := mset.At(i).Obj(); m.Exported() {
+				features[name+"."+m.Name()] = apiFeature{desc: types.TypeString(m.Type(), qf)}
+			}
+		}
+	}
+
+	return features
+}
+
+// CompareGoAPI classifies the differences between two versions of the exported API.
+func CompareGoAPI(oldAPI, newAPI GoAPI) APIDiff {
+	var diff APIDiff
+
+	for _, path := range sortedKeys(oldAPI) {
+		newFeatures, ok := newAPI[path]
+		if !ok {
+			diff.Changes = append(diff.Changes, APIChange{Package: path, Kind: APIIncompatible, Message: "package removed"})
+			continue
+		}
+
+		oldFeatures := oldAPI[path]
+		for _, name := range sortedKeys(oldFeatures) {
+			nf, ok := newFeatures[name]
+			switch {
+			case !ok:
+				diff.Changes = append(diff.Changes, APIChange{Package: path, Name: name, Kind: APIIncompatible, Message: "removed"})
+			case nf.desc != oldFeatures[name].desc:
+				diff.Changes = append(diff.Changes, APIChange{Package: path, Name: name, Kind: APIIncompatible,
+					Message: fmt.Sprintf("changed from %s to %s", oldFeatures[name].desc, nf.desc)})
+			}
+		}
+
+		for _, name := range sortedKeys(newFeatures) {
+			if _, ok := oldFeatures[name]; ok {
+				continue
+			}
+			kind := APICompatible
+			if newFeatures[name].addBreaks {
+				kind = APIIncompatible
+			}
+			diff.Changes = append(diff.Changes, APIChange{Package: path, Name: name, Kind: kind, Message: "added"})
+		}
+	}
+
+	for _, path := range sortedKeys(newAPI) {
+		if _, ok := oldAPI[path]; !ok {
+			diff.Changes = append(diff.Changes, APIChange{Package: path, Kind: APICompatible, Message: "package added"})
+		}
+	}
+
+	return diff
+}
+
+func sortedKeys[M ~map[string]V, V any](m M) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	return keys
+}
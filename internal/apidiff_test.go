@@ -0,0 +1,78 @@
+package internal
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestCompareGoAPI tests the classification of exported API changes
+func TestCompareGoAPI(t *testing.T) {
+	base := GoAPI{
+		"example.com/lib": {
+			"F":   {desc: "func F(x int) error"},
+			"I":   {desc: "type I interface"},
+			"I.M": {desc: "func()", addBreaks: true},
+			"S":   {desc: "type S struct"},
+			"S.A": {desc: "field int"},
+		},
+	}
+
+	testCases := []struct {
+		name         string
+		newAPI       GoAPI
+		expectedKind APIChangeKind
+		expectedLen  int
+	}{
+		{
+			name:         "No changes",
+			newAPI:       base,
+			expectedKind: APINone,
+		},
+		{
+			name: "Struct field added",
+			newAPI: GoAPI{"example.com/lib": {
+				"F": base["example.com/lib"]["F"], "I": base["example.com/lib"]["I"], "I.M": base["example.com/lib"]["I.M"],
+				"S": base["example.com/lib"]["S"], "S.A": base["example.com/lib"]["S.A"], "S.B": {desc: "field string"},
+			}},
+			expectedKind: APICompatible,
+			expectedLen:  1,
+		},
+		{
+			name: "Interface method added",
+			newAPI: GoAPI{"example.com/lib": {
+				"F": base["example.com/lib"]["F"], "I": base["example.com/lib"]["I"], "I.M": base["example.com/lib"]["I.M"],
+				"I.N": {desc: "func()", addBreaks: true}, "S": base["example.com/lib"]["S"], "S.A": base["example.com/lib"]["S.A"],
+			}},
+			expectedKind: APIIncompatible,
+			expectedLen:  1,
+		},
+		{
+			name: "Function signature changed and package added",
+			newAPI: GoAPI{
+				"example.com/lib": {
+					"F": {desc: "func F(x string) error"}, "I": base["example.com/lib"]["I"], "I.M": base["example.com/lib"]["I.M"],
+					"S": base["example.com/lib"]["S"], "S.A": base["example.com/lib"]["S.A"],
+				},
+				"example.com/lib/v2": {},
+			},
+			expectedKind: APIIncompatible,
+			expectedLen:  2,
+		},
+		{
+			name:         "Package removed",
+			newAPI:       GoAPI{},
+			expectedKind: APIIncompatible,
+			expectedLen:  1,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			diff := CompareGoAPI(base, tc.newAPI)
+
+			assert.Equal(t, tc.expectedKind, diff.Kind())
+			assert.Len(t, diff.Changes, tc.expectedLen)
+		})
+	}
+}
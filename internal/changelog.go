@@ -0,0 +1,93 @@
+package internal
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// ChangelogFile is the Keep a Changelog file maintained in the repository root.
+const ChangelogFile = "CHANGELOG.md"
+
+// ErrEmptyUnreleased is returned when the [Unreleased] section of the changelog has no entries.
+var ErrEmptyUnreleased = errors.New("CHANGELOG.md has no entries in the [Unreleased] section")
+
+var (
+	unreleasedHeaderRe = regexp.MustCompile(`^##\s+\[Unreleased\]`)
+	unreleasedLinkRe   = regexp.MustCompile(`^\[Unreleased\]:\s*(\S+)/compare/(\S+)\.\.\.HEAD\s*$`)
+	linkReferenceRe    = regexp.MustCompile(`^\[[^\]]+\]:\s`)
+)
+
+// unreleasedSection returns the line range [start, end) of the [Unreleased] section body.
+func unreleasedSection(lines []string) (int, int, error) {
+	start := -1
+	for i, line := range lines {
+		if unreleasedHeaderRe.MatchString(line) {
+			start = i + 1
+			break
+		}
+	}
+	if start < 0 {
+		return 0, 0, fmt.Errorf("CHANGELOG.md has no [Unreleased] section")
+	}
+
+	end := len(lines)
+	for i := start; i < len(lines); i++ {
+		if strings.HasPrefix(lines[i], "## ") || linkReferenceRe.MatchString(lines[i]) {
+			end = i
+			break
+		}
+	}
+
+	return start, end, nil
+}
+
+// CheckUnreleased verifies that the [Unreleased] section of a Keep a Changelog document has at least one entry.
+// Subsection headers such as "### Added" alone do not count as entries.
+func CheckUnreleased(data []byte) error {
+	lines := strings.Split(string(data), "\n")
+	start, end, err := unreleasedSection(lines)
+	if err != nil {
+		return err
+	}
+
+	for _, line := range lines[start:end] {
+		line = strings.TrimSpace(line)
+		if line != "" && !strings.HasPrefix(line, "#") {
+			return nil
+		}
+	}
+
+	return ErrEmptyUnreleased
+}
+
+// ReleaseUnreleased turns the [Unreleased] section into a section for the given version,
+// adds a fresh [Unreleased] header above it and updates the compare links at the bottom of the document.
+func ReleaseUnreleased(data []byte, version, tag string, date time.Time) ([]byte, error) {
+	lines := strings.Split(string(data), "\n")
+	start, _, err := unreleasedSection(lines)
+	if err != nil {
+		return nil, err
+	}
+
+	header := fmt.Sprintf("## [%s] - %s", version, date.Format(time.DateOnly))
+	out := make([]string, 0, len(lines)+3)
+	out = append(out, lines[:start]...)
+	out = append(out, "", header)
+	out = append(out, lines[start:]...)
+
+	for i, line := range out {
+		m := unreleasedLinkRe.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		base, previous := m[1], m[2]
+		out[i] = fmt.Sprintf("[Unreleased]: %s/compare/%s...HEAD", base, tag)
+		out = append(out[:i+1], append([]string{fmt.Sprintf("[%s]: %s/compare/%s...%s", version, base, previous, tag)}, out[i+1:]...)...)
+		break
+	}
+
+	return []byte(strings.Join(out, "\n")), nil
+}
@@ -0,0 +1,93 @@
+package internal
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testChangelog = `# Changelog
+
+## [Unreleased]
+
+### Added
+- New feature
+
+## [0.0.6] - 2025-03-27
+
+### Added
+- Old feature
+
+[Unreleased]: https://github.com/flaticols/bump/compare/v0.0.6...HEAD
+[0.0.6]: https://github.com/flaticols/bump/compare/v0.0.5...v0.0.6
+`
+
+// TestCheckUnreleased tests detection of empty [Unreleased] sections
+func TestCheckUnreleased(t *testing.T) {
+	testCases := []struct {
+		name        string
+		changelog   string
+		expectError bool
+	}{
+		{
+			name:      "Unreleased entries",
+			changelog: testChangelog,
+		},
+		{
+			name:        "Only subsection headers",
+			changelog:   "## [Unreleased]\n\n### Added\n\n## [0.0.6] - 2025-03-27\n- Old feature\n",
+			expectError: true,
+		},
+		{
+			name:        "Empty section before links",
+			changelog:   "## [Unreleased]\n\n[Unreleased]: https://example.com/compare/v1.0.0...HEAD\n",
+			expectError: true,
+		},
+		{
+			name:        "No unreleased section",
+			changelog:   "# Changelog\n",
+			expectError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := CheckUnreleased([]byte(tc.changelog))
+
+			if tc.expectError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+// TestReleaseUnreleased tests the rewrite of the [Unreleased] section and the compare links
+func TestReleaseUnreleased(t *testing.T) {
+	date := time.Date(2025, 4, 2, 0, 0, 0, 0, time.UTC)
+
+	out, err := ReleaseUnreleased([]byte(testChangelog), "0.1.0", "v0.1.0", date)
+
+	assert.NoError(t, err)
+	assert.Equal(t, `# Changelog
+
+## [Unreleased]
+
+## [0.1.0] - 2025-04-02
+
+### Added
+- New feature
+
+## [0.0.6] - 2025-03-27
+
+### Added
+- Old feature
+
+[Unreleased]: https://github.com/flaticols/bump/compare/v0.1.0...HEAD
+[0.1.0]: https://github.com/flaticols/bump/compare/v0.0.6...v0.1.0
+[0.0.6]: https://github.com/flaticols/bump/compare/v0.0.5...v0.0.6
+`, string(out))
+	assert.ErrorIs(t, CheckUnreleased(out), ErrEmptyUnreleased)
+}
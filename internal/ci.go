@@ -0,0 +1,105 @@
+package internal
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// CI providers detected from the environment.
+const (
+	CIGitHubActions = "github-actions"
+	CIGitLab        = "gitlab"
+	CIBitbucket     = "bitbucket"
+	CICircleCI      = "circleci"
+	CIJenkins       = "jenkins"
+	CIBuildkite     = "buildkite"
+	CIAzure         = "azure-pipelines"
+	CITravis        = "travis"
+	CIGeneric       = "generic"
+)
+
+// CIEnv describes the CI service bump runs on. Branch is the branch being built as announced by the
+// provider, empty when the build is not for a branch, e.g. for a tag.
+type CIEnv struct {
+	Provider string
+	Branch   string
+}
+
+// DetectCI recognises the CI service from its environment variables, read with getenv.
+func DetectCI(getenv func(string) string) (CIEnv, bool) {
+	switch {
+	case getenv("GITHUB_ACTIONS") == "true":
+		branch := getenv("GITHUB_HEAD_REF")
+		if branch == "" && getenv("GITHUB_REF_TYPE") == "branch" {
+			branch = getenv("GITHUB_REF_NAME")
+		}
+		return CIEnv{Provider: CIGitHubActions, Branch: branch}, true
+	case getenv("GITLAB_CI") != "":
+		branch := getenv("CI_COMMIT_BRANCH")
+		if branch == "" {
+			branch = getenv("CI_MERGE_REQUEST_SOURCE_BRANCH_NAME")
+		}
+		return CIEnv{Provider: CIGitLab, Branch: branch}, true
+	case getenv("BITBUCKET_BUILD_NUMBER") != "":
+		return CIEnv{Provider: CIBitbucket, Branch: getenv("BITBUCKET_BRANCH")}, true
+	case getenv("CIRCLECI") == "true":
+		return CIEnv{Provider: CICircleCI, Branch: getenv("CIRCLE_BRANCH")}, true
+	case getenv("JENKINS_URL") != "":
+		branch := getenv("BRANCH_NAME")
+		if branch == "" {
+			branch = strings.TrimPrefix(getenv("GIT_BRANCH"), DefaultRemote+"/")
+		}
+		return CIEnv{Provider: CIJenkins, Branch: branch}, true
+	case getenv("BUILDKITE") == "true":
+		return CIEnv{Provider: CIBuildkite, Branch: getenv("BUILDKITE_BRANCH")}, true
+	case getenv("TF_BUILD") == "True":
+		branch, _ := strings.CutPrefix(getenv("BUILD_SOURCEBRANCH"), "refs/heads/")
+		if strings.HasPrefix(branch, "refs/") {
+			branch = ""
+		}
+		return CIEnv{Provider: CIAzure, Branch: branch}, true
+	case getenv("TRAVIS") == "true":
+		return CIEnv{Provider: CITravis, Branch: getenv("TRAVIS_BRANCH")}, true
+	}
+
+	if ci := getenv("CI"); ci != "" && ci != "false" && ci != "0" {
+		return CIEnv{Provider: CIGeneric}, true
+	}
+	return CIEnv{}, false
+}
+
+// ciBranch returns the branch announced by the CI service, used when HEAD is detached.
+func ciBranch() string {
+	ci, _ := DetectCI(os.Getenv)
+	return ci.Branch
+}
+
+// IsShallow reports whether the repository is a shallow clone, i.e. .git/shallow exists.
+func IsShallow() (bool, error) {
+	cmd := exec.Command("git", "rev-parse", "--git-path", "shallow")
+	output, err := cmd.Output()
+	if err != nil {
+		return false, fmt.Errorf("failed to locate the git directory: %w", err)
+	}
+
+	_, err = os.Stat(strings.TrimSpace(string(output)))
+	if errors.Is(err, os.ErrNotExist) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// UnshallowArgs returns the git fetch arguments that fetch the tags and the history of a shallow clone:
+// the whole history, or depth more commits when depth is positive.
+func UnshallowArgs(depth int) []string {
+	args := []string{"fetch", "--tags"}
+	if depth > 0 {
+		args = append(args, fmt.Sprintf("--deepen=%d", depth))
+	} else {
+		args = append(args, "--unshallow")
+	}
+	return append(args, DefaultRemote)
+}
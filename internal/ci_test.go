@@ -0,0 +1,79 @@
+package internal
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestDetectCI tests the provider and branch detection from the environment
+func TestDetectCI(t *testing.T) {
+	testCases := []struct {
+		name     string
+		env      map[string]string
+		expected CIEnv
+		detected bool
+	}{
+		{
+			name:     "GitHub push",
+			env:      map[string]string{"CI": "true", "GITHUB_ACTIONS": "true", "GITHUB_REF_TYPE": "branch", "GITHUB_REF_NAME": "main"},
+			expected: CIEnv{Provider: CIGitHubActions, Branch: "main"},
+			detected: true,
+		},
+		{
+			name:     "GitHub pull request",
+			env:      map[string]string{"GITHUB_ACTIONS": "true", "GITHUB_HEAD_REF": "feature", "GITHUB_REF_NAME": "12/merge"},
+			expected: CIEnv{Provider: CIGitHubActions, Branch: "feature"},
+			detected: true,
+		},
+		{
+			name:     "GitHub tag",
+			env:      map[string]string{"GITHUB_ACTIONS": "true", "GITHUB_REF_TYPE": "tag", "GITHUB_REF_NAME": "v1.0.0"},
+			expected: CIEnv{Provider: CIGitHubActions},
+			detected: true,
+		},
+		{
+			name:     "GitLab",
+			env:      map[string]string{"CI": "true", "GITLAB_CI": "true", "CI_COMMIT_BRANCH": "trunk"},
+			expected: CIEnv{Provider: CIGitLab, Branch: "trunk"},
+			detected: true,
+		},
+		{
+			name:     "Jenkins",
+			env:      map[string]string{"JENKINS_URL": "https://ci.example.com", "GIT_BRANCH": "origin/master"},
+			expected: CIEnv{Provider: CIJenkins, Branch: "master"},
+			detected: true,
+		},
+		{
+			name:     "Azure Pipelines",
+			env:      map[string]string{"TF_BUILD": "True", "BUILD_SOURCEBRANCH": "refs/heads/main"},
+			expected: CIEnv{Provider: CIAzure, Branch: "main"},
+			detected: true,
+		},
+		{
+			name:     "Generic",
+			env:      map[string]string{"CI": "1"},
+			expected: CIEnv{Provider: CIGeneric},
+			detected: true,
+		},
+		{
+			name:     "Not CI",
+			env:      map[string]string{"CI": "false"},
+			detected: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			env, ok := DetectCI(func(key string) string { return tc.env[key] })
+			assert.Equal(t, tc.detected, ok)
+			assert.Equal(t, tc.expected, env)
+		})
+	}
+}
+
+// TestUnshallowArgs tests fetching the whole history or a limited number of commits
+func TestUnshallowArgs(t *testing.T) {
+	assert.Equal(t, []string{"fetch", "--tags", "--unshallow", "origin"}, UnshallowArgs(0))
+	assert.Equal(t, []string{"fetch", "--tags", "--deepen=50", "origin"}, UnshallowArgs(50))
+}
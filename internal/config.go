@@ -0,0 +1,121 @@
+package internal
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ConfigFileName is the name of the optional configuration file looked up in the repository root.
+const ConfigFileName = ".bump.yaml"
+
+// Config holds the optional per-repository configuration of bump.
+type Config struct {
+	Hooks     HooksConfig     `yaml:"hooks"`
+	Checks    ChecksConfig    `yaml:"checks"`
+	Changelog ChangelogConfig `yaml:"changelog"`
+	Tags      TagsConfig      `yaml:"tags"`
+	Branches  BranchesConfig  `yaml:"branches"`
+	CI        CIConfig        `yaml:"ci"`
+	Release   ReleaseConfig   `yaml:"release"`
+	// VersionFiles are rewritten with the new version and committed before tagging
+	VersionFiles []VersionFile `yaml:"version-files"`
+}
+
+// ChangelogConfig customizes the changelog generated from the commit history.
+type ChangelogConfig struct {
+	// Template is the path to a text/template file rendered for every release.
+	Template string `yaml:"template"`
+}
+
+// TagsConfig sets the rules the tag history is verified against.
+type TagsConfig struct {
+	// Annotated requires every release tag to be an annotated tag.
+	Annotated bool `yaml:"annotated"`
+}
+
+// BranchesConfig sets the branches releases are made from.
+type BranchesConfig struct {
+	// Default overrides the default branch resolved from the remote.
+	Default string `yaml:"default"`
+	// Releasable lists glob patterns of additional branches releases can be made from, e.g. release/*.
+	Releasable []string `yaml:"releasable"`
+}
+
+// CIConfig tunes the CI mode.
+type CIConfig struct {
+	// Deepen fetches this many more commits of a shallow clone instead of its whole history.
+	Deepen int `yaml:"deepen"`
+}
+
+// ReleaseConfig enables creating a release on a forge once the tag is pushed.
+type ReleaseConfig struct {
+	GitHub *GitHubReleaseConfig `yaml:"github"`
+	GitLab *GitLabReleaseConfig `yaml:"gitlab"`
+}
+
+// GitHubReleaseConfig configures releases through the GitHub REST API, or the compatible API of Gitea and Forgejo.
+type GitHubReleaseConfig struct {
+	// APIURL is the base URL of the API, e.g. https://gitea.example.com/api/v1 (default: https://api.github.com).
+	APIURL string `yaml:"api-url"`
+	// Repository is the owner/name of the repository (default: read from the origin remote).
+	Repository string `yaml:"repository"`
+	// TokenEnv is the environment variable the token is read from (default: GITHUB_TOKEN).
+	TokenEnv string `yaml:"token-env"`
+	// Assets lists glob patterns of files uploaded to the release.
+	Assets []string `yaml:"assets"`
+	// Draft creates the release as a draft.
+	Draft bool `yaml:"draft"`
+}
+
+// GitLabReleaseConfig configures releases through the GitLab Releases API.
+type GitLabReleaseConfig struct {
+	// APIURL is the base URL of the API (default: CI_API_V4_URL, then https://gitlab.com/api/v4).
+	APIURL string `yaml:"api-url"`
+	// ProjectID is the numeric ID or the path of the project (default: CI_PROJECT_ID, then read from the origin remote).
+	ProjectID string `yaml:"project-id"`
+	// TokenEnv is the environment variable an access token is read from (default: GITLAB_TOKEN), CI_JOB_TOKEN is used without it.
+	TokenEnv string `yaml:"token-env"`
+	// Milestones are associated with the release.
+	Milestones []string `yaml:"milestones"`
+	// Links are asset links added to the release, $BUMP_* variables in their URLs are expanded.
+	Links []ReleaseLink `yaml:"links"`
+}
+
+// ChecksConfig enables optional preflight check suites.
+type ChecksConfig struct {
+	Go        bool `yaml:"go"`
+	API       bool `yaml:"api"`
+	Changelog bool `yaml:"changelog"`
+}
+
+// HooksConfig lists the shell commands executed around each lifecycle phase.
+type HooksConfig struct {
+	PreBump  []string `yaml:"pre-bump"`
+	PostTag  []string `yaml:"post-tag"`
+	PostPush []string `yaml:"post-push"`
+	PostUndo []string `yaml:"post-undo"`
+}
+
+// LoadConfig reads the configuration file from the given directory.
+// A missing file is not an error and results in an empty configuration.
+func LoadConfig(dir string) (*Config, error) {
+	cfg := &Config{}
+
+	data, err := os.ReadFile(filepath.Join(dir, ConfigFileName))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return cfg, nil
+		}
+		return nil, fmt.Errorf("failed to read %s: %w", ConfigFileName, err)
+	}
+
+	if err := yaml.Unmarshal(data, cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", ConfigFileName, err)
+	}
+
+	return cfg, nil
+}
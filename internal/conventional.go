@@ -0,0 +1,112 @@
+package internal
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Commit is a git commit parsed according to the Conventional Commits specification.
+// Commits that do not follow the specification have an empty Type and their subject as Description.
+type Commit struct {
+	Hash         string
+	ShortHash    string
+	Type         string
+	Scope        string
+	Description  string
+	Body         string
+	Breaking     bool
+	BreakingNote string
+}
+
+// CommitGroup is a set of commits of the same type.
+type CommitGroup struct {
+	Type    string
+	Title   string
+	Commits []Commit
+}
+
+var (
+	conventionalSubjectRe = regexp.MustCompile(`^(\w+)(?:\(([^)]*)\))?(!)?:\s*(.+)$`)
+	breakingFooterRe      = regexp.MustCompile(`(?m)^BREAKING[ -]CHANGE:\s*(.+)$`)
+)
+
+// commitGroupTitles lists the known commit types in the order they are rendered.
+var commitGroupTitles = []struct {
+	Type  string
+	Title string
+}{
+	{"feat", "Features"},
+	{"fix", "Bug Fixes"},
+	{"perf", "Performance Improvements"},
+	{"refactor", "Code Refactoring"},
+	{"revert", "Reverts"},
+	{"docs", "Documentation"},
+	{"test", "Tests"},
+	{"build", "Build System"},
+	{"ci", "Continuous Integration"},
+	{"style", "Styles"},
+	{"chore", "Chores"},
+}
+
+// otherCommitsTitle is the title of the group of commits with unknown or missing types.
+const otherCommitsTitle = "Other Changes"
+
+// ParseCommit parses the subject and body of a commit message.
+func ParseCommit(hash, subject, body string) Commit {
+	c := Commit{
+		Hash:        hash,
+		ShortHash:   hash,
+		Description: strings.TrimSpace(subject),
+		Body:        strings.TrimSpace(body),
+	}
+	if len(hash) > 7 {
+		c.ShortHash = hash[:7]
+	}
+
+	if m := conventionalSubjectRe.FindStringSubmatch(c.Description); m != nil {
+		c.Type = strings.ToLower(m[1])
+		c.Scope = m[2]
+		c.Breaking = m[3] == "!"
+		c.Description = m[4]
+	}
+
+	if m := breakingFooterRe.FindStringSubmatch(c.Body); m != nil {
+		c.Breaking = true
+		c.BreakingNote = strings.TrimSpace(m[1])
+	}
+
+	return c
+}
+
+// GroupCommits groups commits by their type in a stable order and returns the breaking changes separately.
+// Commits keep their relative order within a group.
+func GroupCommits(commits []Commit) (groups []CommitGroup, breaking []Commit) {
+	byType := make(map[string][]Commit)
+	for _, c := range commits {
+		if c.Breaking {
+			breaking = append(breaking, c)
+		}
+		byType[commitGroupType(c.Type)] = append(byType[commitGroupType(c.Type)], c)
+	}
+
+	for _, g := range commitGroupTitles {
+		if cs, ok := byType[g.Type]; ok {
+			groups = append(groups, CommitGroup{Type: g.Type, Title: g.Title, Commits: cs})
+		}
+	}
+	if cs, ok := byType[""]; ok {
+		groups = append(groups, CommitGroup{Title: otherCommitsTitle, Commits: cs})
+	}
+
+	return groups, breaking
+}
+
+// commitGroupType returns the type under which a commit is grouped, unknown types end up in the "other" group.
+func commitGroupType(t string) string {
+	for _, g := range commitGroupTitles {
+		if g.Type == t {
+			return t
+		}
+	}
+	return ""
+}
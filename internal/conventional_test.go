@@ -0,0 +1,71 @@
+package internal
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestParseCommit tests parsing of Conventional Commit messages
+func TestParseCommit(t *testing.T) {
+	testCases := []struct {
+		name     string
+		subject  string
+		body     string
+		expected Commit
+	}{
+		{
+			name:     "Type and scope",
+			subject:  "feat(cli): add changelog command",
+			expected: Commit{Type: "feat", Scope: "cli", Description: "add changelog command"},
+		},
+		{
+			name:     "Breaking marker",
+			subject:  "refactor!: drop old flags",
+			expected: Commit{Type: "refactor", Description: "drop old flags", Breaking: true},
+		},
+		{
+			name:    "Breaking footer",
+			subject: "fix: change exit codes",
+			body:    "Details.\n\nBREAKING CHANGE: scripts must check the new codes",
+			expected: Commit{Type: "fix", Description: "change exit codes", Body: "Details.\n\nBREAKING CHANGE: scripts must check the new codes",
+				Breaking: true, BreakingNote: "scripts must check the new codes"},
+		},
+		{
+			name:     "Not conventional",
+			subject:  "Update README",
+			expected: Commit{Description: "Update README"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			c := ParseCommit("0123456789abcdef", tc.subject, tc.body)
+
+			tc.expected.Hash = "0123456789abcdef"
+			tc.expected.ShortHash = "0123456"
+			assert.Equal(t, tc.expected, c)
+		})
+	}
+}
+
+// TestGroupCommits tests the grouping order and the extraction of breaking changes
+func TestGroupCommits(t *testing.T) {
+	commits := []Commit{
+		{Type: "fix", Description: "one"},
+		{Type: "", Description: "two"},
+		{Type: "feat", Description: "three", Breaking: true},
+		{Type: "wip", Description: "four"},
+		{Type: "fix", Description: "five"},
+	}
+
+	groups, breaking := GroupCommits(commits)
+
+	assert.Len(t, groups, 3)
+	assert.Equal(t, "Features", groups[0].Title)
+	assert.Equal(t, "Bug Fixes", groups[1].Title)
+	assert.Equal(t, []Commit{commits[0], commits[4]}, groups[1].Commits)
+	assert.Equal(t, otherCommitsTitle, groups[2].Title)
+	assert.Equal(t, []Commit{commits[1], commits[3]}, groups[2].Commits)
+	assert.Equal(t, []Commit{commits[2]}, breaking)
+}
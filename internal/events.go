@@ -0,0 +1,148 @@
+package internal
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// EventSchemaVersion is the version of the event format written by EventLog.
+// It is increased whenever a field is removed or changes its meaning; new fields and event types keep the version.
+const EventSchemaVersion = 1
+
+// EventType names what happened in an event.
+type EventType string
+
+const (
+	EventRunStarted       EventType = "run_started"
+	EventRunFinished      EventType = "run_finished"
+	EventCheckStarted     EventType = "check_started"
+	EventCheckFinished    EventType = "check_finished"
+	EventFetchStarted     EventType = "fetch_started"
+	EventFetchFinished    EventType = "fetch_finished"
+	EventCommitCreated    EventType = "commit_created"
+	EventTagCreated       EventType = "tag_created"
+	EventPushStarted      EventType = "push_started"
+	EventPushFinished     EventType = "push_finished"
+	EventRollbackStarted  EventType = "rollback_started"
+	EventRollbackFinished EventType = "rollback_finished"
+	EventReleaseStarted   EventType = "release_started"
+	EventReleaseFinished  EventType = "release_finished"
+)
+
+// Event is a single line of the event log.
+type Event struct {
+	Schema  int       `json:"schema"`
+	Type    EventType `json:"type"`
+	Time    time.Time `json:"time"`
+	Command string    `json:"command,omitempty"`
+	// Name identifies the check, or the ref of a fetch or push
+	Name    string `json:"name,omitempty"`
+	Status  string `json:"status,omitempty"`
+	Message string `json:"message,omitempty"`
+	Version string `json:"version,omitempty"`
+	Tag     string `json:"tag,omitempty"`
+	Commit  string `json:"commit,omitempty"`
+	Remote  string `json:"remote,omitempty"`
+	// DurationMS is set on finished events and measures the time since the matching started event
+	DurationMS *float64 `json:"duration_ms,omitempty"`
+	ErrorCode  string   `json:"error_code,omitempty"`
+}
+
+// EventLog writes events as newline-delimited JSON while a command runs.
+// A nil EventLog discards all events.
+type EventLog struct {
+	mu     sync.Mutex
+	w      io.Writer
+	closer io.Closer
+	now    func() time.Time
+}
+
+// NewEventLog creates an event log writing to w.
+func NewEventLog(w io.Writer) *EventLog {
+	return &EventLog{w: w, now: time.Now}
+}
+
+// OpenEventLog opens the event log target given on the command line: a file descriptor number
+// inherited from the parent process, or a file path which is created or truncated.
+func OpenEventLog(target string) (*EventLog, error) {
+	if fd, err := strconv.ParseUint(target, 10, 32); err == nil {
+		f := os.NewFile(uintptr(fd), "fd"+target)
+		if f == nil {
+			return nil, fmt.Errorf("invalid event file descriptor %s", target)
+		}
+		if _, err := f.Stat(); err != nil {
+			return nil, fmt.Errorf("event file descriptor %s is not open: %w", target, err)
+		}
+		l := NewEventLog(f)
+		l.closer = f
+		return l, nil
+	}
+
+	f, err := os.Create(target)
+	if err != nil {
+		return nil, fmt.Errorf("error opening event log: %w", err)
+	}
+	l := NewEventLog(f)
+	l.closer = f
+	return l, nil
+}
+
+// Emit stamps the event with the schema version and the current time and writes it.
+// Write errors are ignored, a broken event consumer must not break the release.
+func (l *EventLog) Emit(e Event) {
+	if l == nil {
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	e.Schema = EventSchemaVersion
+	if e.Time.IsZero() {
+		e.Time = l.now()
+	}
+	data, err := json.Marshal(e)
+	if err != nil {
+		return
+	}
+	_, _ = l.w.Write(append(data, '\n'))
+}
+
+// Start emits the started event of a step and returns a function that emits its finished event
+// with the duration of the step. The finished event inherits the fields of the started one
+// and takes the status, message and error code of the event passed to the function.
+func (l *EventLog) Start(started, finished EventType, e Event) func(result Event) {
+	if l == nil {
+		return func(Event) {}
+	}
+
+	e.Type = started
+	e.Time = l.now()
+	l.Emit(e)
+
+	begin := e.Time
+	return func(result Event) {
+		done := e
+		done.Type = finished
+		done.Time = l.now()
+		done.Status = result.Status
+		done.Message = result.Message
+		done.ErrorCode = result.ErrorCode
+		d := float64(done.Time.Sub(begin)) / float64(time.Millisecond)
+		done.DurationMS = &d
+		l.Emit(done)
+	}
+}
+
+// Close closes the underlying file, if the log owns one.
+func (l *EventLog) Close() error {
+	if l == nil || l.closer == nil {
+		return nil
+	}
+	return l.closer.Close()
+}
@@ -0,0 +1,81 @@
+package internal
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestEventLog tests that events are written as versioned NDJSON lines and steps carry their duration
+func TestEventLog(t *testing.T) {
+	var buf bytes.Buffer
+	log := NewEventLog(&buf)
+
+	clock := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	log.now = func() time.Time {
+		clock = clock.Add(250 * time.Millisecond)
+		return clock
+	}
+
+	done := log.Start(EventPushStarted, EventPushFinished, Event{Name: "v1.2.4", Tag: "v1.2.4", Remote: "origin"})
+	log.Emit(Event{Type: EventTagCreated, Tag: "v1.2.4"})
+	done(Event{Status: "failed", Message: "rejected", ErrorCode: "push_rejected"})
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	assert.Len(t, lines, 3)
+
+	events := make([]Event, len(lines))
+	for i, line := range lines {
+		assert.NoError(t, json.Unmarshal([]byte(line), &events[i]))
+		assert.Equal(t, EventSchemaVersion, events[i].Schema)
+	}
+
+	assert.Equal(t, EventPushStarted, events[0].Type)
+	assert.Nil(t, events[0].DurationMS)
+	assert.Equal(t, EventTagCreated, events[1].Type)
+
+	finished := events[2]
+	assert.Equal(t, EventPushFinished, finished.Type)
+	assert.Equal(t, "v1.2.4", finished.Name)
+	assert.Equal(t, "origin", finished.Remote)
+	assert.Equal(t, "failed", finished.Status)
+	assert.Equal(t, "rejected", finished.Message)
+	assert.Equal(t, "push_rejected", finished.ErrorCode)
+	if assert.NotNil(t, finished.DurationMS) {
+		assert.Equal(t, 500.0, *finished.DurationMS)
+	}
+	assert.True(t, finished.Time.After(events[0].Time))
+}
+
+// TestNilEventLog tests that a missing event log discards events
+func TestNilEventLog(t *testing.T) {
+	var log *EventLog
+	log.Emit(Event{Type: EventRunStarted})
+	log.Start(EventCheckStarted, EventCheckFinished, Event{Name: "go_vet"})(Event{Status: "ok"})
+	assert.NoError(t, log.Close())
+}
+
+// TestOpenEventLog tests opening the event log by path and by file descriptor
+func TestOpenEventLog(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.ndjson")
+	log, err := OpenEventLog(path)
+	assert.NoError(t, err)
+	log.Emit(Event{Type: EventRunStarted, Command: "bump"})
+	assert.NoError(t, log.Close())
+
+	data, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), `"type":"run_started"`)
+
+	_, err = OpenEventLog("987")
+	assert.Error(t, err)
+
+	_, err = OpenEventLog(filepath.Join(t.TempDir(), "missing", "events.ndjson"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,361 @@
+package internal
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// DefaultGitHubAPIURL is the REST API of github.com.
+const DefaultGitHubAPIURL = "https://api.github.com"
+
+// DefaultGitHubTokenEnv is the environment variable the GitHub token is read from by default.
+const DefaultGitHubTokenEnv = "GITHUB_TOKEN"
+
+// ReleaseRequest describes the release created on a forge for a pushed tag.
+type ReleaseRequest struct {
+	Tag        string
+	Name       string
+	Body       string
+	Prerelease bool
+	Draft      bool
+	// Assets are the paths of the files uploaded to the release
+	Assets []string
+	// Milestones and Links are only supported by GitLab
+	Milestones []string
+	Links      []ReleaseLink
+}
+
+// ReleaseLink is a link to a release asset hosted elsewhere, e.g. in the package registry.
+type ReleaseLink struct {
+	Name string `yaml:"name" json:"name"`
+	URL  string `yaml:"url" json:"url"`
+	// Type is other, runbook, image or package
+	Type string `yaml:"type" json:"link_type,omitempty"`
+}
+
+// Releaser creates the release of a pushed tag on a forge and returns the URL of the release page.
+type Releaser interface {
+	CreateRelease(ctx context.Context, r ReleaseRequest) (string, error)
+}
+
+// ForgeError is an unexpected response of a forge API.
+type ForgeError struct {
+	Method     string
+	URL        string
+	StatusCode int
+	Message    string
+}
+
+// Transient reports whether the request may succeed when it is sent again.
+func (e ForgeError) Transient() bool {
+	return e.StatusCode == http.StatusTooManyRequests || e.StatusCode >= http.StatusInternalServerError
+}
+
+func (e ForgeError) Error() string {
+	if e.Message != "" {
+		return fmt.Sprintf("%s %s: %d %s", e.Method, e.URL, e.StatusCode, e.Message)
+	}
+	return fmt.Sprintf("%s %s: %d %s", e.Method, e.URL, e.StatusCode, http.StatusText(e.StatusCode))
+}
+
+// GitHubReleaser creates releases through the GitHub REST API. Gitea and Forgejo implement the same
+// endpoints under their /api/v1 base URL, GitHub Enterprise under /api/v3.
+type GitHubReleaser struct {
+	APIURL string
+	// Repository is the owner/name of the repository
+	Repository string
+	Token      string
+	Client     *http.Client
+	// Attempts is the number of times a request is sent while it fails transiently (default: 3)
+	Attempts int
+	// Delay is the time before the first retry, doubled after every retry (default: 2s)
+	Delay time.Duration
+	// OnRetry is called before every retry
+	OnRetry func(attempt int, err error)
+}
+
+type githubRelease struct {
+	ID        int64  `json:"id"`
+	TagName   string `json:"tag_name"`
+	HTMLURL   string `json:"html_url"`
+	UploadURL string `json:"upload_url"`
+}
+
+// CreateRelease creates the release and uploads its assets. It returns the URL of the release page.
+func (g *GitHubReleaser) CreateRelease(ctx context.Context, r ReleaseRequest) (string, error) {
+	payload, err := json.Marshal(map[string]any{
+		"tag_name":   r.Tag,
+		"name":       r.Name,
+		"body":       r.Body,
+		"draft":      r.Draft,
+		"prerelease": r.Prerelease,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	endpoint := fmt.Sprintf("%s/repos/%s/releases", strings.TrimSuffix(g.APIURL, "/"), g.Repository)
+	var created githubRelease
+	err = g.retry(ctx, func(retried bool) error {
+		err := g.do(ctx, http.MethodPost, endpoint, "application/json", bytes.NewReader(payload), &created)
+		// a retried request may find the release created by an attempt whose response was lost
+		if retried && alreadyExists(err) {
+			if release, getErr := g.findRelease(ctx, endpoint, r); getErr == nil {
+				created = release
+				return nil
+			}
+		}
+		return err
+	})
+	if err != nil {
+		return "", fmt.Errorf("error creating release %s: %w", r.Tag, err)
+	}
+
+	for _, asset := range r.Assets {
+		err := g.retry(ctx, func(retried bool) error {
+			err := g.uploadAsset(ctx, endpoint, created, asset)
+			if retried && alreadyExists(err) {
+				return nil
+			}
+			return err
+		})
+		if err != nil {
+			return created.HTMLURL, fmt.Errorf("error uploading %s to release %s: %w", asset, r.Tag, err)
+		}
+	}
+
+	return created.HTMLURL, nil
+}
+
+// uploadAsset uploads a file to the upload URL GitHub returns, or as multipart form to the assets
+// endpoint of Gitea and Forgejo, which return no upload URL.
+// findRelease reads back the release of the tag. Drafts are not returned by the tag endpoint,
+// they are looked up in the list of releases.
+func (g *GitHubReleaser) findRelease(ctx context.Context, endpoint string, r ReleaseRequest) (githubRelease, error) {
+	var release githubRelease
+	if !r.Draft {
+		err := g.do(ctx, http.MethodGet, endpoint+"/tags/"+url.PathEscape(r.Tag), "", nil, &release)
+		return release, err
+	}
+
+	var releases []githubRelease
+	if err := g.do(ctx, http.MethodGet, endpoint+"?per_page=100", "", nil, &releases); err != nil {
+		return release, err
+	}
+	for _, release := range releases {
+		if release.TagName == r.Tag {
+			return release, nil
+		}
+	}
+	return release, fmt.Errorf("no release of %s found", r.Tag)
+}
+
+func (g *GitHubReleaser) uploadAsset(ctx context.Context, endpoint string, release githubRelease, path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	name := url.QueryEscape(filepath.Base(path))
+
+	if release.UploadURL != "" {
+		// the upload URL is a URI template such as .../assets{?name,label}
+		base, _, _ := strings.Cut(release.UploadURL, "{")
+		return g.do(ctx, http.MethodPost, base+"?name="+name, "application/octet-stream", bytes.NewReader(data), nil)
+	}
+
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	part, err := form.CreateFormFile("attachment", filepath.Base(path))
+	if err != nil {
+		return err
+	}
+	if _, err := part.Write(data); err != nil {
+		return err
+	}
+	if err := form.Close(); err != nil {
+		return err
+	}
+	return g.do(ctx, http.MethodPost, fmt.Sprintf("%s/%d/assets?name=%s", endpoint, release.ID, name), form.FormDataContentType(), &body, nil)
+}
+
+// retry sends a request with fn until it succeeds, fails permanently or runs out of attempts.
+func (g *GitHubReleaser) retry(ctx context.Context, fn func(retried bool) error) error {
+	return retryRequest(ctx, g.Attempts, g.Delay, g.OnRetry, fn)
+}
+
+func (g *GitHubReleaser) do(ctx context.Context, method, endpoint, contentType string, body io.Reader, result any) error {
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, body)
+	if err != nil {
+		return err
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("Authorization", "token "+g.Token)
+
+	return doJSON(g.Client, req, result)
+}
+
+// doJSON sends a request and decodes the JSON response into result, turning non-2xx responses into a ForgeError.
+func doJSON(client *http.Client, req *http.Request, result any) error {
+	if client == nil {
+		client = &http.Client{Timeout: time.Minute}
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return ForgeError{Method: req.Method, URL: req.URL.Redacted(), StatusCode: resp.StatusCode, Message: errorMessage(data)}
+	}
+
+	if result == nil || len(data) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(data, result); err != nil {
+		return fmt.Errorf("invalid response of %s %s: %w", req.Method, req.URL.Redacted(), err)
+	}
+	return nil
+}
+
+// errorMessage reads the message of an error response. GitHub and Gitea send a string, GitLab also sends
+// objects of validation errors or an error field.
+func errorMessage(data []byte) string {
+	var apiErr struct {
+		Message json.RawMessage `json:"message"`
+		Error   string          `json:"error"`
+	}
+	if err := json.Unmarshal(data, &apiErr); err != nil {
+		return ""
+	}
+
+	var message string
+	if err := json.Unmarshal(apiErr.Message, &message); err == nil {
+		return message
+	}
+	if len(apiErr.Message) > 0 && string(apiErr.Message) != "null" {
+		return string(apiErr.Message)
+	}
+	return apiErr.Error
+}
+
+// retry calls fn up to attempts times while it fails with a network error or a transient ForgeError,
+// doubling the delay after every attempt. onRetry, if set, is called before every retry.
+func retry(ctx context.Context, attempts int, delay time.Duration, onRetry func(attempt int, err error), fn func() error) error {
+	for attempt := 1; ; attempt++ {
+		err := fn()
+		if err == nil || attempt >= attempts || !transient(err) {
+			return err
+		}
+		if onRetry != nil {
+			onRetry(attempt, err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(delay):
+		}
+		delay *= 2
+	}
+}
+
+// retryRequest calls retry with the defaults of retryPolicy for unset values. fn is told whether
+// an earlier attempt was already sent, so that it can recognise what that attempt created.
+func retryRequest(ctx context.Context, attempts int, delay time.Duration, onRetry func(attempt int, err error), fn func(retried bool) error) error {
+	attempts, delay = retryPolicy(attempts, delay)
+	sent := 0
+	return retry(ctx, attempts, delay, onRetry, func() error {
+		sent++
+		return fn(sent > 1)
+	})
+}
+
+// retryPolicy returns the number of attempts and the first retry delay of a releaser, with defaults for unset values.
+func retryPolicy(attempts int, delay time.Duration) (int, time.Duration) {
+	if attempts == 0 {
+		attempts = 3
+	}
+	if delay == 0 {
+		delay = 2 * time.Second
+	}
+	return attempts, delay
+}
+
+// alreadyExists reports whether a request was rejected because what it creates exists already:
+// GitHub answers 422, Gitea, Forgejo and GitLab 409.
+func alreadyExists(err error) bool {
+	var forgeErr ForgeError
+	return errors.As(err, &forgeErr) &&
+		(forgeErr.StatusCode == http.StatusUnprocessableEntity || forgeErr.StatusCode == http.StatusConflict)
+}
+
+// transient reports whether a request failed for a reason that sending it again may fix.
+func transient(err error) bool {
+	var forgeErr ForgeError
+	if errors.As(err, &forgeErr) {
+		return forgeErr.Transient()
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr) || errors.Is(err, io.ErrUnexpectedEOF)
+}
+
+// remoteURLPattern captures the path of the repository in SSH, scp-like and HTTP remote URLs.
+var remoteURLPattern = regexp.MustCompile(`^(?:[a-z+]+://)?(?:[^@/]+@)?[^:/]+(?::\d+)?[:/](.+?)(?:\.git)?/?$`)
+
+// RepositoryPath returns the path of the repository on its host, e.g. owner/name, from a remote URL.
+func RepositoryPath(remoteURL string) (string, error) {
+	m := remoteURLPattern.FindStringSubmatch(strings.TrimSpace(remoteURL))
+	if m == nil || !strings.Contains(m[1], "/") {
+		return "", fmt.Errorf("cannot read the repository from the remote URL '%s'", remoteURL)
+	}
+	return m[1], nil
+}
+
+// RemoteURL returns the URL of the default remote.
+func RemoteURL() (string, error) {
+	output, err := exec.Command("git", "remote", "get-url", DefaultRemote).CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("error reading the URL of %s: %v - %s", DefaultRemote, err, string(output))
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// ExpandAssets resolves the glob patterns of release assets to files. Every pattern must match a file.
+func ExpandAssets(patterns []string) ([]string, error) {
+	var files []string
+	for _, pattern := range patterns {
+		matches, err := filepath.Glob(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid asset pattern '%s': %w", pattern, err)
+		}
+		if len(matches) == 0 {
+			return nil, fmt.Errorf("asset pattern '%s' matches no file", pattern)
+		}
+		files = append(files, matches...)
+	}
+	return files, nil
+}
@@ -0,0 +1,205 @@
+package internal
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestGitHubReleaserCreateRelease tests the release payload and the asset upload through the upload URL GitHub returns
+func TestGitHubReleaserCreateRelease(t *testing.T) {
+	asset := filepath.Join(t.TempDir(), "bump_linux.tar.gz")
+	assert.NoError(t, os.WriteFile(asset, []byte("archive"), 0o644))
+
+	var payload map[string]any
+	var uploaded string
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "token secret", r.Header.Get("Authorization"))
+		switch r.URL.Path {
+		case "/repos/flaticols/bump/releases":
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&payload))
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"id": 7, "html_url": "https://github.com/flaticols/bump/releases/tag/v1.3.0-rc.0",` +
+				`"upload_url": "` + server.URL + `/uploads/7/assets{?name,label}"}`))
+		case "/uploads/7/assets":
+			assert.Equal(t, "bump_linux.tar.gz", r.URL.Query().Get("name"))
+			data, _ := io.ReadAll(r.Body)
+			uploaded = string(data)
+			w.WriteHeader(http.StatusCreated)
+		default:
+			t.Errorf("unexpected request %s", r.URL)
+		}
+	}))
+	defer server.Close()
+
+	g := &GitHubReleaser{APIURL: server.URL, Repository: "flaticols/bump", Token: "secret"}
+	url, err := g.CreateRelease(context.Background(), ReleaseRequest{
+		Tag: "v1.3.0-rc.0", Name: "v1.3.0-rc.0", Body: "notes", Prerelease: true, Assets: []string{asset},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "https://github.com/flaticols/bump/releases/tag/v1.3.0-rc.0", url)
+	assert.Equal(t, map[string]any{"tag_name": "v1.3.0-rc.0", "name": "v1.3.0-rc.0", "body": "notes", "draft": false, "prerelease": true}, payload)
+	assert.Equal(t, "archive", uploaded)
+}
+
+// TestGitHubReleaserGiteaAssets tests the multipart asset upload of Gitea and Forgejo, which return no upload URL
+func TestGitHubReleaserGiteaAssets(t *testing.T) {
+	asset := filepath.Join(t.TempDir(), "checksums.txt")
+	assert.NoError(t, os.WriteFile(asset, []byte("sum"), 0o644))
+
+	var uploaded string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/repos/team/app/releases":
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"id": 3, "html_url": "https://gitea.example.com/team/app/releases/tag/v1.0.0"}`))
+		case "/api/v1/repos/team/app/releases/3/assets":
+			f, _, err := r.FormFile("attachment")
+			if assert.NoError(t, err) {
+				data, _ := io.ReadAll(f)
+				uploaded = string(data)
+			}
+			w.WriteHeader(http.StatusCreated)
+		default:
+			t.Errorf("unexpected request %s", r.URL)
+		}
+	}))
+	defer server.Close()
+
+	g := &GitHubReleaser{APIURL: server.URL + "/api/v1/", Repository: "team/app", Token: "secret"}
+	_, err := g.CreateRelease(context.Background(), ReleaseRequest{Tag: "v1.0.0", Assets: []string{asset}})
+	assert.NoError(t, err)
+	assert.Equal(t, "sum", uploaded)
+}
+
+// TestGitHubReleaserRetry tests that transient failures are retried, that a release created by a lost response
+// is read back instead of failing as a duplicate and that asset uploads are retried as well
+func TestGitHubReleaserRetry(t *testing.T) {
+	asset := filepath.Join(t.TempDir(), "checksums.txt")
+	assert.NoError(t, os.WriteFile(asset, []byte("sum"), 0o644))
+
+	var requests []string
+	uploads := 0
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		switch r.Method + " " + r.URL.Path {
+		case "POST /repos/flaticols/bump/releases":
+			if len(requests) == 1 {
+				w.WriteHeader(http.StatusBadGateway)
+				return
+			}
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			_, _ = w.Write([]byte(`{"message": "Validation Failed", "errors": [{"resource": "Release", "code": "already_exists", "field": "tag_name"}]}`))
+		case "GET /repos/flaticols/bump/releases/tags/v1.0.0":
+			_, _ = w.Write([]byte(`{"id": 7, "html_url": "https://github.com/flaticols/bump/releases/tag/v1.0.0",` +
+				`"upload_url": "` + server.URL + `/uploads/7/assets{?name,label}"}`))
+		case "POST /uploads/7/assets":
+			uploads++
+			if uploads == 1 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			w.WriteHeader(http.StatusCreated)
+		default:
+			t.Errorf("unexpected request %s", r.URL)
+		}
+	}))
+	defer server.Close()
+
+	var retries []int
+	g := &GitHubReleaser{
+		APIURL: server.URL, Repository: "flaticols/bump", Token: "secret", Delay: time.Millisecond,
+		OnRetry: func(attempt int, err error) { retries = append(retries, attempt) },
+	}
+	url, err := g.CreateRelease(context.Background(), ReleaseRequest{Tag: "v1.0.0", Assets: []string{asset}})
+	assert.NoError(t, err)
+	assert.Equal(t, "https://github.com/flaticols/bump/releases/tag/v1.0.0", url)
+	assert.Equal(t, []int{1, 1}, retries)
+	assert.Equal(t, []string{
+		"POST /repos/flaticols/bump/releases",
+		"POST /repos/flaticols/bump/releases",
+		"GET /repos/flaticols/bump/releases/tags/v1.0.0",
+		"POST /uploads/7/assets",
+		"POST /uploads/7/assets",
+	}, requests)
+}
+
+// TestGitHubReleaserRetryDraft tests that a draft created by a lost response is found in the list of releases
+func TestGitHubReleaserRetryDraft(t *testing.T) {
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		switch r.Method + " " + r.URL.Path {
+		case "POST /repos/flaticols/bump/releases":
+			if len(requests) == 1 {
+				w.WriteHeader(http.StatusBadGateway)
+				return
+			}
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			_, _ = w.Write([]byte(`{"message": "Validation Failed"}`))
+		case "GET /repos/flaticols/bump/releases":
+			_, _ = w.Write([]byte(`[{"id": 8, "tag_name": "v1.1.0", "html_url": "https://github.com/flaticols/bump/releases/tag/untagged-2"},` +
+				`{"id": 7, "tag_name": "v1.0.0", "html_url": "https://github.com/flaticols/bump/releases/tag/untagged-1"}]`))
+		default:
+			t.Errorf("unexpected request %s", r.URL)
+		}
+	}))
+	defer server.Close()
+
+	g := &GitHubReleaser{APIURL: server.URL, Repository: "flaticols/bump", Token: "secret", Delay: time.Millisecond}
+	url, err := g.CreateRelease(context.Background(), ReleaseRequest{Tag: "v1.0.0", Draft: true})
+	assert.NoError(t, err)
+	assert.Equal(t, "https://github.com/flaticols/bump/releases/tag/untagged-1", url)
+	assert.Equal(t, []string{
+		"POST /repos/flaticols/bump/releases",
+		"POST /repos/flaticols/bump/releases",
+		"GET /repos/flaticols/bump/releases",
+	}, requests)
+}
+
+// TestGitHubReleaserError tests that API errors carry the status and message of the response and are not retried
+func TestGitHubReleaserError(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		_, _ = w.Write([]byte(`{"message": "Validation Failed"}`))
+	}))
+	defer server.Close()
+
+	g := &GitHubReleaser{APIURL: server.URL, Repository: "flaticols/bump", Token: "secret"}
+	_, err := g.CreateRelease(context.Background(), ReleaseRequest{Tag: "v1.0.0"})
+	var forgeErr ForgeError
+	if assert.ErrorAs(t, err, &forgeErr) {
+		assert.Equal(t, http.StatusUnprocessableEntity, forgeErr.StatusCode)
+		assert.Equal(t, "Validation Failed", forgeErr.Message)
+	}
+	assert.Equal(t, 1, calls, "validation errors are not retried")
+}
+
+// TestRepositoryPath tests reading owner/name from SSH, scp-like and HTTP remote URLs
+func TestRepositoryPath(t *testing.T) {
+	for remote, want := range map[string]string{
+		"git@github.com:flaticols/bump.git":               "flaticols/bump",
+		"https://github.com/flaticols/bump.git":           "flaticols/bump",
+		"https://gitea.example.com:3000/team/app":         "team/app",
+		"ssh://git@gitlab.example.com:2222/group/sub/app": "group/sub/app",
+	} {
+		got, err := RepositoryPath(remote)
+		assert.NoError(t, err, remote)
+		assert.Equal(t, want, got, remote)
+	}
+
+	_, err := RepositoryPath("/tmp/remote.git")
+	assert.Error(t, err)
+}
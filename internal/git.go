@@ -3,14 +3,20 @@ package internal
 import (
 	"fmt"
 	"os/exec"
+	"path"
 	"slices"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/Masterminds/semver/v3"
 )
 
 const DefaultVersion = "0.0.1"
 
+// DefaultRemote is the remote bump fetches from and pushes tags to.
+const DefaultRemote = "origin"
+
 type SemVerTagError struct {
 	NoTags bool
 	Tag    string
@@ -24,8 +30,6 @@ func (e SemVerTagError) Error() string {
 	return fmt.Sprintf("error parsing semver tag: '%s'", e.Tag)
 }
 
-var defaultBranches = []string{"main", "master", "develop", "feature", "release", "hotfix", "bugfix", "latest"}
-
 type GitState struct {
 }
 
@@ -42,9 +46,27 @@ func (gs *GitState) CheckLocalChanges() (bool, error) {
 	return len(strings.TrimSpace(string(output))) > 0, nil
 }
 
-// CheckRemoteChanges checks if there are changes in the remote repository that are not present in the local repository.
-// It fetches the latest changes from the remote and compares the local branch with the tracking branch to detect differences.
-func (gs *GitState) CheckRemoteChanges(allowNoRemotes bool) (bool, error) {
+// NoUpstreamError is returned when the current branch does not track a remote branch.
+type NoUpstreamError struct {
+	Branch string
+}
+
+func (e NoUpstreamError) Error() string {
+	return fmt.Sprintf("branch %s has no upstream branch, set one with git push -u or git branch --set-upstream-to", e.Branch)
+}
+
+// Upstream is the branch the current branch tracks and how many commits the current branch is ahead of and behind it.
+type Upstream struct {
+	Name   string
+	Ahead  int
+	Behind int
+}
+
+// CheckUpstream fetches the remote of the upstream branch of the current branch (`@{upstream}`)
+// and counts the commits that are only local (ahead) and only on the upstream branch (behind).
+// Without remotes an empty Upstream is returned when allowNoRemotes is set.
+// A NoUpstreamError is returned when the current branch tracks no remote branch.
+func (gs *GitState) CheckUpstream(allowNoRemotes bool) (Upstream, error) {
 	// First check if remotes exist
 	remoteCmd := exec.Command("git", "remote")
 	remoteOutput, err := remoteCmd.Output()
@@ -52,168 +74,178 @@ func (gs *GitState) CheckRemoteChanges(allowNoRemotes bool) (bool, error) {
 	// If no remotes exist
 	if err != nil || len(strings.TrimSpace(string(remoteOutput))) == 0 {
 		if !allowNoRemotes {
-			return false, fmt.Errorf("no remotes found in repository")
+			return Upstream{}, fmt.Errorf("no remotes found in repository")
 		}
 		// If we don't want to error on no remotes, just return no changes
-		return false, nil
+		return Upstream{}, nil
 	}
 
-	// Fetch the latest changes from remote
-	fetchCmd := exec.Command("git", "fetch", "origin")
-	if err := fetchCmd.Run(); err != nil {
-		return false, fmt.Errorf("failed to fetch from remote: %w", err)
-	}
-
-	// Get current branch
-	branchCmd := exec.Command("git", "symbolic-ref", "HEAD")
-	branchOutput, branchErr := branchCmd.Output()
-
-	// If we can't get the branch, try the fallback
-	if branchErr != nil {
-		fallbackCmd := exec.Command("git", "rev-parse", "--abbrev-ref", "HEAD")
-		fallbackOutput, fallbackErr := fallbackCmd.Output()
-		if fallbackErr != nil {
-			return false, fmt.Errorf("failed to get current branch: %w", fallbackErr)
+	// Without a refspec git fetches the remote the current branch tracks
+	ref, fetchArgs := "@{upstream}", []string{"fetch"}
+	var up Upstream
+
+	upstreamCmd := exec.Command("git", "rev-parse", "--abbrev-ref", "--symbolic-full-name", "@{upstream}")
+	upstreamOutput, err := upstreamCmd.Output()
+	if err == nil {
+		up.Name = strings.TrimSpace(string(upstreamOutput))
+	} else if branch := ciBranch(); branch != "" && isDetached() {
+		// CI services check out a detached HEAD, compare it with the remote branch being built
+		up.Name = DefaultRemote + "/" + branch
+		ref = "refs/remotes/" + up.Name
+		fetchArgs = []string{"fetch", DefaultRemote, "+refs/heads/" + branch + ":" + ref}
+	} else {
+		branch, branchErr := currentBranch()
+		if branchErr != nil {
+			return Upstream{}, branchErr
 		}
-		branchOutput = fallbackOutput
+		return Upstream{}, NoUpstreamError{Branch: branch}
 	}
 
-	currentBranch := strings.TrimSpace(string(branchOutput))
-	currentBranch = strings.TrimPrefix(currentBranch, "refs/heads/")
+	fetchCmd := exec.Command("git", fetchArgs...)
+	if output, err := fetchCmd.CombinedOutput(); err != nil {
+		return up, fmt.Errorf("failed to fetch %s: %v - %s", up.Name, err, string(output))
+	}
 
-	// Check if there are remote changes not in local, first try with origin/main
-	cmd := exec.Command("git", "log", "HEAD..origin/main", "--oneline")
-	output, err := cmd.Output()
+	countCmd := exec.Command("git", "rev-list", "--left-right", "--count", "HEAD..."+ref)
+	countOutput, err := countCmd.CombinedOutput()
 	if err != nil {
-		// Try with current branch
-		cmd = exec.Command("git", "log", fmt.Sprintf("HEAD..origin/%s", currentBranch), "--oneline")
-		output, err = cmd.Output()
-		if err != nil {
-			return false, fmt.Errorf("failed to check remote changes: %w", err)
-		}
+		return up, fmt.Errorf("failed to compare with %s: %v - %s", up.Name, err, string(countOutput))
 	}
-
-	return len(strings.TrimSpace(string(output))) > 0, nil
+	up.Ahead, up.Behind, err = ParseLeftRightCount(string(countOutput))
+	return up, err
 }
 
-// HasRemoteUnfetchedTags checks if there are tags in the remote repository that haven't been fetched locally.
-// Returns true if unfetched tags exist, false otherwise, and an error if the process fails.
-func (gs *GitState) HasRemoteUnfetchedTags() (bool, error) {
-	// First check if remotes exist
-	remoteCmd := exec.Command("git", "remote")
-	remoteOutput, err := remoteCmd.Output()
-	if err != nil || len(strings.TrimSpace(string(remoteOutput))) == 0 {
-		return false, fmt.Errorf("no remotes found in repository")
+// ParseLeftRightCount parses the output of `git rev-list --left-right --count A...B`
+// into the number of commits only in A and only in B.
+func ParseLeftRightCount(output string) (int, int, error) {
+	fields := strings.Fields(output)
+	if len(fields) != 2 {
+		return 0, 0, fmt.Errorf("unexpected commit count: %q", output)
 	}
-
-	// Get local tags before fetching
-	localTagsCmd := exec.Command("git", "tag")
-	localTagsOutput, err := localTagsCmd.Output()
+	left, err := strconv.Atoi(fields[0])
 	if err != nil {
-		return false, fmt.Errorf("failed to get local tags: %w", err)
+		return 0, 0, fmt.Errorf("unexpected commit count: %q", output)
 	}
-	localTags := strings.Split(strings.TrimSpace(string(localTagsOutput)), "\n")
-	localTagSet := make(map[string]bool)
-	for _, tag := range localTags {
-		if tag != "" {
-			localTagSet[tag] = true
-		}
+	right, err := strconv.Atoi(fields[1])
+	if err != nil {
+		return 0, 0, fmt.Errorf("unexpected commit count: %q", output)
 	}
+	return left, right, nil
+}
 
-	// Get remote tags without fetching them
-	lsRemoteCmd := exec.Command("git", "ls-remote", "--tags", "origin")
-	lsRemoteOutput, err := lsRemoteCmd.Output()
+// IsDefaultBranch returns the current Git branch and whether a release can be made from it:
+// it is the default branch of the repository or matches one of the releasable branch patterns.
+func (gs *GitState) IsDefaultBranch(branches BranchesConfig) (string, bool, error) {
+	b, err := currentBranch()
 	if err != nil {
-		return false, fmt.Errorf("failed to list remote tags: %w", err)
+		return "", false, err
 	}
 
-	// Parse the output to extract remote tags
-	remoteTags := strings.Split(strings.TrimSpace(string(lsRemoteOutput)), "\n")
-	for _, line := range remoteTags {
-		if line == "" {
-			continue
-		}
-
-		// Extract tag name from line like "hash refs/tags/tagname"
-		parts := strings.Split(line, "\t")
-		if len(parts) < 2 {
-			continue
-		}
-
-		refPath := parts[1]
-		// Skip tag pointers (^{})
-		if strings.Contains(refPath, "^{}") {
-			continue
-		}
-
-		// Extract the tag name from refs/tags/tagname
-		tagName := strings.TrimPrefix(refPath, "refs/tags/")
-
-		// If this remote tag is not in our local tags, we have unfetched tags
-		if !localTagSet[tagName] {
-			return true, nil
-		}
+	if matchesBranchPattern(b, branches.Releasable) {
+		return b, true, nil
 	}
 
-	// No unfetched tags found
-	return false, nil
+	defaultBranch, err := DefaultBranch(branches.Default)
+	if err != nil {
+		return b, false, err
+	}
+	return b, b == defaultBranch, nil
 }
 
-// IsDefaultBranch checks if the current Git branch is one of the predefined default branches and returns a boolean and an error if one occurs.
-func (gs *GitState) IsDefaultBranch() (string, bool, error) {
+// currentBranch returns the name of the checked out branch.
+func currentBranch() (string, error) {
 	// Try the normal approach first
 	cmd := exec.Command("git", "rev-parse", "--abbrev-ref", "HEAD")
 	output, err := cmd.CombinedOutput()
-
-	// If the command fails, try the fallback method
-	if err != nil {
-		// Try using symbolic-ref instead which works for repos without commits
-		fallbackCmd := exec.Command("git", "symbolic-ref", "HEAD")
-		fallbackOutput, fallbackErr := fallbackCmd.Output()
-
-		if fallbackErr != nil {
-			return "", false, fmt.Errorf("failed to get current branch: %w", fallbackErr)
+	if err == nil {
+		b := strings.TrimSpace(string(output))
+		// A detached HEAD, as checked out by CI services, is built for the branch they announce
+		if b == "HEAD" {
+			if ci := ciBranch(); ci != "" {
+				return ci, nil
+			}
 		}
+		return b, nil
+	}
 
-		// Remove the refs/heads/ prefix from the output
-		branchRef := strings.TrimSpace(string(fallbackOutput))
-		b := strings.TrimPrefix(branchRef, "refs/heads/")
-		return b, slices.Contains(defaultBranches, b), nil
+	// Try using symbolic-ref instead which works for repos without commits
+	fallbackCmd := exec.Command("git", "symbolic-ref", "HEAD")
+	fallbackOutput, fallbackErr := fallbackCmd.Output()
+	if fallbackErr != nil {
+		return "", fmt.Errorf("failed to get current branch: %w", fallbackErr)
 	}
 
-	b := strings.TrimSpace(string(output))
-	return b, slices.Contains(defaultBranches, b), nil
+	// Remove the refs/heads/ prefix from the output
+	return strings.TrimPrefix(strings.TrimSpace(string(fallbackOutput)), "refs/heads/"), nil
 }
 
-func (gs *GitState) HasUnpushedChanges(currentBranch string) (bool, error) {
-	remoteCmd := exec.Command("git", "remote")
-	remoteOutput, err := remoteCmd.Output()
+// isDetached reports whether HEAD points to a commit instead of a branch.
+func isDetached() bool {
+	return exec.Command("git", "symbolic-ref", "-q", "HEAD").Run() != nil
+}
 
-	if err != nil || len(strings.TrimSpace(string(remoteOutput))) == 0 {
-		return false, nil
+// matchesBranchPattern reports whether the branch matches one of the glob patterns, e.g. release/*.
+func matchesBranchPattern(branch string, patterns []string) bool {
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, branch); ok {
+			return true
+		}
 	}
+	return false
+}
 
-	cmd := exec.Command("git", "rev-list", "--count", fmt.Sprintf("origin/%s..%s", currentBranch, currentBranch))
-	output, err := cmd.Output()
+// DefaultBranch returns the default branch of the repository. A non-empty override, e.g. from the
+// configuration, wins. Otherwise the branch refs/remotes/<remote>/HEAD points to is used, then the HEAD
+// branch announced by the remote, and in repositories without remote the first local main, master or trunk.
+func DefaultBranch(override string) (string, error) {
+	if override != "" {
+		return override, nil
+	}
 
-	if err != nil {
-		checkRemoteBranchCmd := exec.Command("git", "ls-remote", "--heads", "origin", currentBranch)
-		remoteBranchOutput, _ := checkRemoteBranchCmd.Output()
-
-		if len(strings.TrimSpace(string(remoteBranchOutput))) == 0 {
-			checkLocalCommitsCmd := exec.Command("git", "rev-list", "--count", currentBranch)
-			localCommitsOutput, localErr := checkLocalCommitsCmd.Output()
-			if localErr != nil {
-				return false, fmt.Errorf("failed to check local commits: %w", localErr)
-			}
+	cmd := exec.Command("git", "symbolic-ref", "--short", "refs/remotes/"+DefaultRemote+"/HEAD")
+	if output, err := cmd.Output(); err == nil {
+		return strings.TrimPrefix(strings.TrimSpace(string(output)), DefaultRemote+"/"), nil
+	}
 
-			count := strings.TrimSpace(string(localCommitsOutput))
-			return count != "0", nil
+	if exec.Command("git", "remote", "get-url", DefaultRemote).Run() == nil {
+		cmd = exec.Command("git", "ls-remote", "--symref", DefaultRemote, "HEAD")
+		output, err := cmd.CombinedOutput()
+		if err != nil {
+			return "", fmt.Errorf("error resolving the default branch of %s: %v - %s", DefaultRemote, err, string(output))
+		}
+		if b := ParseSymref(string(output)); b != "" {
+			return b, nil
 		}
-		return false, fmt.Errorf("failed to check unpushed changes: %w", err)
 	}
-	count := strings.TrimSpace(string(output))
-	return count != "0", nil
+
+	for _, b := range []string{"main", "master", "trunk"} {
+		if exec.Command("git", "show-ref", "--verify", "--quiet", "refs/heads/"+b).Run() == nil {
+			return b, nil
+		}
+	}
+	return "", fmt.Errorf("cannot determine the default branch, set branches.default in %s", ConfigFileName)
+}
+
+// ParseSymref returns the branch HEAD points to in the output of `git ls-remote --symref <remote> HEAD`.
+func ParseSymref(output string) string {
+	for _, line := range strings.Split(output, "\n") {
+		ref, name, ok := strings.Cut(line, "\t")
+		if !ok || name != "HEAD" {
+			continue
+		}
+		if target, ok := strings.CutPrefix(ref, "ref: "); ok {
+			return strings.TrimPrefix(target, "refs/heads/")
+		}
+	}
+	return ""
+}
+
+// BranchRef returns the ref of a local branch, or of the branch of the default remote when there is no local one.
+func BranchRef(branch string) string {
+	if exec.Command("git", "show-ref", "--verify", "--quiet", "refs/heads/"+branch).Run() == nil {
+		return "refs/heads/" + branch
+	}
+	return "refs/remotes/" + DefaultRemote + "/" + branch
 }
 
 // getLatestGitTag retrieves the latest Git tag from the current repository.
@@ -266,9 +298,58 @@ func getLatestGitTag() (string, error) {
 	return highestTag, nil
 }
 
+// SemverTags returns the names of all semver tags in the repository ordered from the lowest to the highest version.
+func SemverTags() ([]string, error) {
+	cmd := exec.Command("git", "tag")
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("error getting git tags: %w", err)
+	}
+
+	type semverTag struct {
+		name string
+		ver  *semver.Version
+	}
+	var tags []semverTag
+	for _, tag := range strings.Fields(string(output)) {
+		if v, err := semver.NewVersion(tag); err == nil {
+			tags = append(tags, semverTag{name: tag, ver: v})
+		}
+	}
+
+	slices.SortStableFunc(tags, func(a, b semverTag) int {
+		return a.ver.Compare(b.ver)
+	})
+
+	names := make([]string, len(tags))
+	for i, t := range tags {
+		names[i] = t.name
+	}
+	return names, nil
+}
+
+// TagDate returns the creation date of the tag, or of the tagged commit for lightweight tags.
+func TagDate(tag string) (time.Time, error) {
+	cmd := exec.Command("git", "for-each-ref", "--format=%(creatordate:iso-strict)", "refs/tags/"+tag)
+	output, err := cmd.Output()
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to get date of tag %s: %w", tag, err)
+	}
+	return time.Parse(time.RFC3339, strings.TrimSpace(string(output)))
+}
+
+// LatestTag returns the name of the highest semver tag in the repository.
+func LatestTag() (string, error) {
+	return getLatestGitTag()
+}
+
 // SetGitTag creates a new Git tag with the specified name and returns an error if the process fails or the tag could not be created.
-func (gs *GitState) SetGitTag(tag string) error {
+// An annotated tag carries its name as the message.
+func (gs *GitState) SetGitTag(tag string, annotated bool) error {
 	cmd := exec.Command("git", "tag", tag)
+	if annotated {
+		cmd = exec.Command("git", "tag", "-a", "-m", tag, tag)
+	}
 	output, err := cmd.CombinedOutput()
 	if err != nil {
 		return fmt.Errorf("error setting git tag: %v - %s", err, string(output))
@@ -278,7 +359,7 @@ func (gs *GitState) SetGitTag(tag string) error {
 
 // PushGitTag pushes the specified Git tag to the origin remote repository. It returns an error if the command execution fails.
 func (gs *GitState) PushGitTag(tag string) error {
-	cmd := exec.Command("git", "push", "origin", tag)
+	cmd := exec.Command("git", "push", DefaultRemote, tag)
 	output, err := cmd.CombinedOutput()
 	if err != nil {
 		return fmt.Errorf("error pushing git tag: %v - %s", err, string(output))
@@ -286,6 +367,57 @@ func (gs *GitState) PushGitTag(tag string) error {
 	return nil
 }
 
+// GetHeadCommit returns the full SHA of the commit HEAD points to.
+func (gs *GitState) GetHeadCommit() (string, error) {
+	cmd := exec.Command("git", "rev-parse", "HEAD")
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve HEAD commit: %w", err)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// CommitFiles stages the given files and records them in a new commit with the given message.
+func (gs *GitState) CommitFiles(message string, files ...string) error {
+	addCmd := exec.Command("git", append([]string{"add", "--"}, files...)...)
+	if output, err := addCmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("error staging files: %v - %s", err, string(output))
+	}
+
+	commitCmd := exec.Command("git", append([]string{"commit", "-m", message, "--"}, files...)...)
+	if output, err := commitCmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("error creating commit: %v - %s", err, string(output))
+	}
+	return nil
+}
+
+// PushRef returns the refspec the current branch is pushed with: HEAD, or HEAD:refs/heads/<branch>
+// for the detached HEAD of a CI checkout. It fails on a detached HEAD when no CI service announces the branch.
+func PushRef() (string, error) {
+	if !isDetached() {
+		return "HEAD", nil
+	}
+	if branch := ciBranch(); branch != "" {
+		return "HEAD:refs/heads/" + branch, nil
+	}
+	return "", fmt.Errorf("HEAD is detached and the branch to push to is unknown, check out a branch")
+}
+
+// PushCurrentBranch pushes the current branch to the origin remote repository.
+func (gs *GitState) PushCurrentBranch() error {
+	ref, err := PushRef()
+	if err != nil {
+		return err
+	}
+
+	cmd := exec.Command("git", "push", DefaultRemote, ref)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("error pushing branch: %v - %s", err, string(output))
+	}
+	return nil
+}
+
 // GetCurrentVersion retrieves the current version state from Git tags.
 // Returns the current version as a semver.Version and an error if unsuccessful.
 func (gs *GitState) GetCurrentVersion() (*semver.Version, error) {
@@ -317,7 +449,7 @@ func (gs *GitState) RemoveLocalGitTag(tag string) error {
 
 // removeRemoteGitTag deletes a git tag from the remote repository
 func (gs *GitState) RemoveRemoteGitTag(tag string) error {
-	cmd := exec.Command("git", "push", "--delete", "origin", tag)
+	cmd := exec.Command("git", "push", "--delete", DefaultRemote, tag)
 	output, err := cmd.CombinedOutput()
 	if err != nil {
 		return fmt.Errorf("error removing remote git tag: %v - %s", err, string(output))
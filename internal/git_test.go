@@ -1,14 +1,54 @@
 package internal
 
 import (
+	"os"
 	"os/exec"
-	"slices"
+	"path/filepath"
 	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 )
 
+// testRepo creates a bare origin with a commit on main and changes into a clone of it, outside of any CI
+// environment. It returns the path of the origin.
+func testRepo(t *testing.T) string {
+	t.Helper()
+	for _, name := range []string{"CI", "GITHUB_ACTIONS", "GITLAB_CI", "BITBUCKET_BUILD_NUMBER", "CIRCLECI", "JENKINS_URL", "BUILDKITE", "TF_BUILD", "TRAVIS"} {
+		t.Setenv(name, "")
+	}
+	t.Setenv("GIT_CONFIG_GLOBAL", os.DevNull)
+	t.Setenv("GIT_CONFIG_NOSYSTEM", "1")
+	t.Setenv("GIT_AUTHOR_NAME", "bump")
+	t.Setenv("GIT_AUTHOR_EMAIL", "bump@example.com")
+	t.Setenv("GIT_COMMITTER_NAME", "bump")
+	t.Setenv("GIT_COMMITTER_EMAIL", "bump@example.com")
+
+	dir := t.TempDir()
+	seed := filepath.Join(dir, "seed")
+	origin := filepath.Join(dir, "origin.git")
+	work := filepath.Join(dir, "work")
+	runGit(t, dir, "init", "-q", "-b", "main", seed)
+	runGit(t, seed, "commit", "-q", "--allow-empty", "-m", "initial")
+	runGit(t, dir, "clone", "-q", "--bare", seed, origin)
+	runGit(t, dir, "clone", "-q", origin, work)
+
+	t.Chdir(work)
+	return origin
+}
+
+// runGit runs git in dir and fails the test when it fails.
+func runGit(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("git %s: %v - %s", strings.Join(args, " "), err, output)
+	}
+	return strings.TrimSpace(string(output))
+}
+
 // CommandRunner is an interface that allows us to mock exec.Command
 type CommandRunner interface {
 	Run(name string, args ...string) ([]byte, error)
@@ -89,26 +129,6 @@ func (gs *TestableGitState) CheckLocalChanges() (bool, error) {
 	return len(strings.TrimSpace(string(output))) > 0, nil
 }
 
-// IsDefaultBranch checks if current branch is a default branch
-func (gs *TestableGitState) IsDefaultBranch() (string, bool, error) {
-	output, err := gs.runGitCommand("rev-parse", "--abbrev-ref", "HEAD")
-
-	if err != nil {
-		// Fallback
-		output, err = gs.runGitCommand("symbolic-ref", "HEAD")
-		if err != nil {
-			return "", false, err
-		}
-
-		branchRef := strings.TrimSpace(string(output))
-		b := strings.TrimPrefix(branchRef, "refs/heads/")
-		return b, slices.Contains(defaultBranches, b), nil
-	}
-
-	b := strings.TrimSpace(string(output))
-	return b, slices.Contains(defaultBranches, b), nil
-}
-
 // TestCheckLocalChanges tests the CheckLocalChanges method
 func TestCheckLocalChanges(t *testing.T) {
 	testCases := []struct {
@@ -156,59 +176,214 @@ func TestCheckLocalChanges(t *testing.T) {
 	}
 }
 
-// TestIsDefaultBranch tests the IsDefaultBranch method
+// TestIsDefaultBranch tests the default and releasable branch check against a clone of a bare origin
 func TestIsDefaultBranch(t *testing.T) {
+	testRepo(t)
+	runGit(t, ".", "branch", "feature")
+	runGit(t, ".", "branch", "release/1.x")
+
 	testCases := []struct {
 		name           string
-		mockOutput     string
-		mockError      error
-		fallbackOutput string
-		fallbackError  error
-		expectedBranch string
+		branch         string
+		branches       BranchesConfig
 		expectedResult bool
-		expectError    bool
 	}{
-		{
-			name:           "Main branch",
-			mockOutput:     "main",
-			mockError:      nil,
-			expectedBranch: "main",
-			expectedResult: true,
-			expectError:    false,
-		},
-		{
-			name:           "Feature branch",
-			mockOutput:     "feature-branch",
-			mockError:      nil,
-			expectedBranch: "feature-branch",
-			expectedResult: false,
-			expectError:    false,
-		},
+		{name: "Default branch of origin", branch: "main", expectedResult: true},
+		{name: "Feature branch", branch: "feature", expectedResult: false},
+		{name: "Releasable branch", branch: "release/1.x", branches: BranchesConfig{Releasable: []string{"release/*"}}, expectedResult: true},
+		{name: "Configured default branch", branch: "feature", branches: BranchesConfig{Default: "feature"}, expectedResult: true},
+		{name: "Configured default branch replaces the remote one", branch: "main", branches: BranchesConfig{Default: "feature"}, expectedResult: false},
 	}
 
+	gs := &GitState{}
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			// Create mock runner
-			mockRunner := NewMockCommandRunner()
-			mockRunner.SetOutput("git rev-parse --abbrev-ref HEAD", []byte(tc.mockOutput), tc.mockError)
-			if tc.fallbackOutput != "" {
-				mockRunner.SetOutput("git symbolic-ref HEAD", []byte(tc.fallbackOutput), tc.fallbackError)
-			}
+			runGit(t, ".", "checkout", "-q", tc.branch)
 
-			// Create testable GitState
-			gs := &TestableGitState{CmdRunner: mockRunner}
+			branch, isDefault, err := gs.IsDefaultBranch(tc.branches)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.branch, branch)
+			assert.Equal(t, tc.expectedResult, isDefault)
+		})
+	}
+}
 
-			// Test the method (we only implement this specific method for TestableGitState)
-			branch, isDefault, err := gs.IsDefaultBranch()
+// TestDefaultBranch tests the resolution order of the default branch
+func TestDefaultBranch(t *testing.T) {
+	origin := testRepo(t)
+	runGit(t, ".", "push", "-q", "origin", "main:trunk")
 
-			if tc.expectError {
-				assert.Error(t, err)
-			} else {
-				assert.NoError(t, err)
-				assert.Equal(t, tc.expectedBranch, branch)
-				assert.Equal(t, tc.expectedResult, isDefault)
-			}
-		})
+	branch, err := DefaultBranch("develop")
+	assert.NoError(t, err)
+	assert.Equal(t, "develop", branch, "the override wins")
+
+	runGit(t, ".", "remote", "set-head", "origin", "trunk")
+	branch, err = DefaultBranch("")
+	assert.NoError(t, err)
+	assert.Equal(t, "trunk", branch, "refs/remotes/origin/HEAD is read first")
+
+	runGit(t, ".", "remote", "set-head", "origin", "--delete")
+	runGit(t, origin, "symbolic-ref", "HEAD", "refs/heads/trunk")
+	branch, err = DefaultBranch("")
+	assert.NoError(t, err)
+	assert.Equal(t, "trunk", branch, "the HEAD of the remote is asked without refs/remotes/origin/HEAD")
+
+	runGit(t, ".", "remote", "remove", "origin")
+	runGit(t, ".", "branch", "-m", "main", "master")
+	branch, err = DefaultBranch("")
+	assert.NoError(t, err)
+	assert.Equal(t, "master", branch, "a local branch is used without remote")
+
+	runGit(t, ".", "branch", "-m", "master", "develop")
+	_, err = DefaultBranch("")
+	assert.Error(t, err)
+}
+
+// TestParseSymref tests reading the default branch from `git ls-remote --symref`
+func TestParseSymref(t *testing.T) {
+	output := "ref: refs/heads/trunk\tHEAD\n4b825dc642cb6eb9a060e54bf8d69288fbee4904\tHEAD\n"
+	assert.Equal(t, "trunk", ParseSymref(output))
+	assert.Equal(t, "", ParseSymref("4b825dc642cb6eb9a060e54bf8d69288fbee4904\tHEAD\n"))
+}
+
+// TestMatchesBranchPattern tests the releasable branch globs
+func TestMatchesBranchPattern(t *testing.T) {
+	patterns := []string{"release/*", "hotfix-*"}
+	assert.True(t, matchesBranchPattern("release/2.x", patterns))
+	assert.True(t, matchesBranchPattern("hotfix-login", patterns))
+	assert.False(t, matchesBranchPattern("release/2.x/fix", patterns))
+	assert.False(t, matchesBranchPattern("feature", patterns))
+	assert.False(t, matchesBranchPattern("main", nil))
+}
+
+// TestParseLeftRightCount tests reading the ahead and behind counts of `git rev-list --left-right --count`
+func TestParseLeftRightCount(t *testing.T) {
+	ahead, behind, err := ParseLeftRightCount("2\t5\n")
+	assert.NoError(t, err)
+	assert.Equal(t, 2, ahead)
+	assert.Equal(t, 5, behind)
+
+	_, _, err = ParseLeftRightCount("fatal: no upstream configured")
+	assert.Error(t, err)
+}
+
+// TestCheckUpstream tests the comparison with the upstream branch, and with the branch announced by CI on a detached HEAD
+func TestCheckUpstream(t *testing.T) {
+	origin := testRepo(t)
+	gs := &GitState{}
+
+	up, err := gs.CheckUpstream(false)
+	assert.NoError(t, err)
+	assert.Equal(t, Upstream{Name: "origin/main"}, up)
+
+	// a commit pushed by someone else is fetched and counted as behind
+	other := filepath.Join(t.TempDir(), "other")
+	runGit(t, ".", "clone", "-q", origin, other)
+	runGit(t, other, "commit", "-q", "--allow-empty", "-m", "remote")
+	runGit(t, other, "push", "-q", "origin", "main")
+	runGit(t, ".", "commit", "-q", "--allow-empty", "-m", "local 1")
+	runGit(t, ".", "commit", "-q", "--allow-empty", "-m", "local 2")
+
+	up, err = gs.CheckUpstream(false)
+	assert.NoError(t, err)
+	assert.Equal(t, Upstream{Name: "origin/main", Ahead: 2, Behind: 1}, up)
+
+	t.Run("No upstream", func(t *testing.T) {
+		runGit(t, ".", "checkout", "-q", "-b", "feature")
+		defer runGit(t, ".", "checkout", "-q", "main")
+
+		_, err := gs.CheckUpstream(false)
+		assert.Equal(t, NoUpstreamError{Branch: "feature"}, err)
+	})
+
+	t.Run("Detached HEAD in CI", func(t *testing.T) {
+		runGit(t, ".", "checkout", "-q", "--detach", "origin/main")
+		defer runGit(t, ".", "checkout", "-q", "main")
+		runGit(t, other, "commit", "-q", "--allow-empty", "-m", "remote 2")
+		runGit(t, other, "push", "-q", "origin", "main")
+
+		_, err := gs.CheckUpstream(false)
+		assert.Equal(t, NoUpstreamError{Branch: "HEAD"}, err)
+
+		t.Setenv("GITHUB_ACTIONS", "true")
+		t.Setenv("GITHUB_REF_TYPE", "branch")
+		t.Setenv("GITHUB_REF_NAME", "main")
+		up, err := gs.CheckUpstream(false)
+		assert.NoError(t, err)
+		assert.Equal(t, Upstream{Name: "origin/main", Behind: 1}, up)
+	})
+
+	t.Run("No remotes", func(t *testing.T) {
+		runGit(t, ".", "remote", "remove", "origin")
+
+		up, err := gs.CheckUpstream(true)
+		assert.NoError(t, err)
+		assert.Equal(t, Upstream{}, up)
+
+		_, err = gs.CheckUpstream(false)
+		assert.Error(t, err)
+	})
+}
+
+// TestPushCurrentBranch tests pushing a commit made on the detached HEAD of a CI checkout to the announced branch
+func TestPushCurrentBranch(t *testing.T) {
+	origin := testRepo(t)
+	gs := &GitState{}
+
+	runGit(t, ".", "checkout", "-q", "--detach")
+	runGit(t, ".", "commit", "-q", "--allow-empty", "-m", "chore(release): v1.0.0")
+	head := runGit(t, ".", "rev-parse", "HEAD")
+
+	_, err := PushRef()
+	assert.Error(t, err)
+	assert.Error(t, gs.PushCurrentBranch())
+	assert.NotEqual(t, head, runGit(t, origin, "rev-parse", "main"))
+
+	t.Setenv("GITLAB_CI", "true")
+	t.Setenv("CI_COMMIT_BRANCH", "main")
+	ref, err := PushRef()
+	assert.NoError(t, err)
+	assert.Equal(t, "HEAD:refs/heads/main", ref)
+	assert.NoError(t, gs.PushCurrentBranch())
+	assert.Equal(t, head, runGit(t, origin, "rev-parse", "main"))
+
+	runGit(t, ".", "checkout", "-q", "main")
+	ref, err = PushRef()
+	assert.NoError(t, err)
+	assert.Equal(t, "HEAD", ref)
+}
+
+// TestGetCurrentVersionNoTags tests that a repository without tags is told apart from an invalid tag
+func TestGetCurrentVersionNoTags(t *testing.T) {
+	testRepo(t)
+	gs := &GitState{}
+
+	_, err := gs.GetCurrentVersion()
+	var tagErr SemVerTagError
+	if assert.ErrorAs(t, err, &tagErr) {
+		assert.True(t, tagErr.NoTags)
+	}
+
+	runGit(t, ".", "tag", "v1.2.3")
+	ver, err := gs.GetCurrentVersion()
+	assert.NoError(t, err)
+	assert.Equal(t, "1.2.3", ver.String())
+}
+
+// TestSetGitTagAnnotated tests that a tag created with tags.annotated passes the lightweight tag check
+func TestSetGitTagAnnotated(t *testing.T) {
+	testRepo(t)
+	gs := &GitState{}
+
+	assert.NoError(t, gs.SetGitTag("v1.0.0", false))
+	assert.NoError(t, gs.SetGitTag("v1.1.0", true))
+	assert.Equal(t, "v1.1.0", runGit(t, ".", "tag", "-l", "--format=%(contents:subject)", "v1.1.0"))
+
+	tags, err := ListTags("v")
+	assert.NoError(t, err)
+	problems := LightweightTags(tags)
+	if assert.Len(t, problems, 1) {
+		assert.Equal(t, "v1.0.0", problems[0].Tag)
 	}
 }
 
@@ -0,0 +1,59 @@
+package internal
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// GitHubOutput is a step output written to the file named by GITHUB_OUTPUT.
+type GitHubOutput struct {
+	Name  string
+	Value string
+}
+
+// FormatGitHubOutputs formats step outputs as name=value lines. Multi-line values are written
+// between a random delimiter, as required by GitHub Actions.
+func FormatGitHubOutputs(outputs []GitHubOutput) (string, error) {
+	var b strings.Builder
+	for _, o := range outputs {
+		if !strings.ContainsAny(o.Value, "\r\n") {
+			fmt.Fprintf(&b, "%s=%s\n", o.Name, o.Value)
+			continue
+		}
+
+		random := make([]byte, 8)
+		if _, err := rand.Read(random); err != nil {
+			return "", err
+		}
+		delimiter := "ghadelimiter_" + hex.EncodeToString(random)
+		fmt.Fprintf(&b, "%s<<%s\n%s\n%s\n", o.Name, delimiter, o.Value, delimiter)
+	}
+	return b.String(), nil
+}
+
+// AppendFile appends data to a file, creating it when missing, as GitHub Actions expects
+// for the files named by GITHUB_OUTPUT and GITHUB_STEP_SUMMARY.
+func AppendFile(path, data string) error {
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("error opening %s: %w", path, err)
+	}
+	if _, err := f.WriteString(data); err != nil {
+		f.Close()
+		return fmt.Errorf("error writing %s: %w", path, err)
+	}
+	return f.Close()
+}
+
+// GitHubAnnotation formats a workflow command that annotates the run, level being error, warning or notice.
+func GitHubAnnotation(level, title, message string) string {
+	escape := strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A")
+	property := strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A", ":", "%3A", ",", "%2C")
+	if title == "" {
+		return fmt.Sprintf("::%s::%s", level, escape.Replace(message))
+	}
+	return fmt.Sprintf("::%s title=%s::%s", level, property.Replace(title), escape.Replace(message))
+}
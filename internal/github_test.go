@@ -0,0 +1,38 @@
+package internal
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestFormatGitHubOutputs tests single-line outputs and the delimiter of multi-line outputs
+func TestFormatGitHubOutputs(t *testing.T) {
+	out, err := FormatGitHubOutputs([]GitHubOutput{{Name: "version", Value: "1.2.4"}, {Name: "bumped", Value: "true"}})
+	assert.NoError(t, err)
+	assert.Equal(t, "version=1.2.4\nbumped=true\n", out)
+
+	out, err = FormatGitHubOutputs([]GitHubOutput{{Name: "notes", Value: "a\nb"}})
+	assert.NoError(t, err)
+	assert.Regexp(t, regexp.MustCompile(`^notes<<(ghadelimiter_[0-9a-f]{16})\na\nb\n(ghadelimiter_[0-9a-f]{16})\n$`), out)
+}
+
+// TestAppendFile tests that GitHub files are appended to, not overwritten
+func TestAppendFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "output")
+	assert.NoError(t, AppendFile(path, "a=1\n"))
+	assert.NoError(t, AppendFile(path, "b=2\n"))
+
+	data, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, "a=1\nb=2\n", string(data))
+}
+
+// TestGitHubAnnotation tests the escaping of workflow commands
+func TestGitHubAnnotation(t *testing.T) {
+	assert.Equal(t, "::error::100%25 broken%0Anext line", GitHubAnnotation("error", "", "100% broken\nnext line"))
+	assert.Equal(t, "::warning title=remote_changes%3A main%2C dev::behind", GitHubAnnotation("warning", "remote_changes: main, dev", "behind"))
+}
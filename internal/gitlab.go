@@ -0,0 +1,128 @@
+package internal
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// DefaultGitLabDotenv and DefaultGitLabReleaseNotes are the files written under GitLab CI when no path is given.
+const (
+	DefaultGitLabDotenv       = "bump.env"
+	DefaultGitLabReleaseNotes = "release-notes.md"
+)
+
+// DotenvVar is a variable of a GitLab dotenv report.
+type DotenvVar struct {
+	Name  string
+	Value string
+}
+
+// FormatDotenv formats variables as NAME=value lines for an `artifacts:reports:dotenv` report,
+// which supports neither multi-line values nor quotes.
+func FormatDotenv(vars []DotenvVar) (string, error) {
+	var b strings.Builder
+	for _, v := range vars {
+		if strings.ContainsAny(v.Value, "\r\n") {
+			return "", fmt.Errorf("dotenv variable %s cannot span several lines", v.Name)
+		}
+		fmt.Fprintf(&b, "%s=%s\n", v.Name, v.Value)
+	}
+	return b.String(), nil
+}
+
+// DefaultGitLabAPIURL is the REST API of gitlab.com.
+const DefaultGitLabAPIURL = "https://gitlab.com/api/v4"
+
+// DefaultGitLabTokenEnv is the environment variable a personal or project access token is read from by default.
+const DefaultGitLabTokenEnv = "GITLAB_TOKEN"
+
+// GitLabReleaser creates releases through the GitLab Releases API. It never creates the tag itself,
+// the release is attached to the pushed tag.
+type GitLabReleaser struct {
+	APIURL string
+	// ProjectID is the numeric ID or the path of the project, e.g. group/app
+	ProjectID string
+	Token     string
+	// JobToken sends Token as the CI_JOB_TOKEN of a GitLab CI job instead of an access token
+	JobToken bool
+	Client   *http.Client
+	// Attempts is the number of times a request is sent while it fails transiently (default: 3)
+	Attempts int
+	// Delay is the time before the first retry, doubled after every retry (default: 2s)
+	Delay time.Duration
+	// OnRetry is called before every retry
+	OnRetry func(attempt int, err error)
+}
+
+type gitlabRelease struct {
+	Links struct {
+		Self string `json:"self"`
+	} `json:"_links"`
+}
+
+// CreateRelease creates the release of the tag with its milestones and asset links and returns the URL
+// of the release page. Assets are not uploaded, GitLab releases link to files hosted elsewhere.
+func (g *GitLabReleaser) CreateRelease(ctx context.Context, r ReleaseRequest) (string, error) {
+	links := r.Links
+	if links == nil {
+		links = []ReleaseLink{}
+	}
+	payload := map[string]any{
+		"tag_name":    r.Tag,
+		"name":        r.Name,
+		"description": r.Body,
+		"assets":      map[string]any{"links": links},
+	}
+	if len(r.Milestones) > 0 {
+		payload["milestones"] = r.Milestones
+	}
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return "", err
+	}
+
+	endpoint := fmt.Sprintf("%s/projects/%s/releases", strings.TrimSuffix(g.APIURL, "/"), url.PathEscape(g.ProjectID))
+	var created gitlabRelease
+	err = g.retry(ctx, func(retried bool) error {
+		err := g.do(ctx, http.MethodPost, endpoint, bytes.NewReader(data), &created)
+		// a retried request may find the release created by an attempt whose response was lost
+		if retried && alreadyExists(err) {
+			if getErr := g.do(ctx, http.MethodGet, endpoint+"/"+url.PathEscape(r.Tag), nil, &created); getErr == nil {
+				return nil
+			}
+		}
+		return err
+	})
+	if err != nil {
+		return "", fmt.Errorf("error creating release %s: %w", r.Tag, err)
+	}
+
+	return created.Links.Self, nil
+}
+
+// retry sends a request with fn until it succeeds, fails permanently or runs out of attempts.
+func (g *GitLabReleaser) retry(ctx context.Context, fn func(retried bool) error) error {
+	return retryRequest(ctx, g.Attempts, g.Delay, g.OnRetry, fn)
+}
+
+func (g *GitLabReleaser) do(ctx context.Context, method, endpoint string, body io.Reader, result any) error {
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if g.JobToken {
+		req.Header.Set("JOB-TOKEN", g.Token)
+	} else {
+		req.Header.Set("PRIVATE-TOKEN", g.Token)
+	}
+
+	return doJSON(g.Client, req, result)
+}
@@ -0,0 +1,97 @@
+package internal
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestFormatDotenv tests the dotenv report lines and the rejection of multi-line values
+func TestFormatDotenv(t *testing.T) {
+	out, err := FormatDotenv([]DotenvVar{{Name: "BUMP_VERSION", Value: "1.2.4"}, {Name: "BUMP_PREVIOUS", Value: ""}})
+	assert.NoError(t, err)
+	assert.Equal(t, "BUMP_VERSION=1.2.4\nBUMP_PREVIOUS=\n", out)
+
+	_, err = FormatDotenv([]DotenvVar{{Name: "BUMP_NOTES", Value: "a\nb"}})
+	assert.Error(t, err)
+}
+
+// TestGitLabReleaserCreateRelease tests the release payload with milestones and links, and the escaped project path
+func TestGitLabReleaserCreateRelease(t *testing.T) {
+	var payload map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v4/projects/group%2Fapp/releases", r.URL.EscapedPath())
+		assert.Equal(t, "secret", r.Header.Get("PRIVATE-TOKEN"))
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&payload))
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"_links": {"self": "https://gitlab.example.com/group/app/-/releases/v1.3.0"}}`))
+	}))
+	defer server.Close()
+
+	g := &GitLabReleaser{APIURL: server.URL + "/api/v4", ProjectID: "group/app", Token: "secret"}
+	url, err := g.CreateRelease(context.Background(), ReleaseRequest{
+		Tag: "v1.3.0", Name: "v1.3.0", Body: "notes", Milestones: []string{"1.3"},
+		Links: []ReleaseLink{{Name: "linux", URL: "https://example.com/app_linux.tar.gz", Type: "package"}},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "https://gitlab.example.com/group/app/-/releases/v1.3.0", url)
+	assert.Equal(t, map[string]any{
+		"tag_name": "v1.3.0", "name": "v1.3.0", "description": "notes", "milestones": []any{"1.3"},
+		"assets": map[string]any{"links": []any{map[string]any{"name": "linux", "url": "https://example.com/app_linux.tar.gz", "link_type": "package"}}},
+	}, payload)
+}
+
+// TestGitLabReleaserRetry tests that transient failures are retried and that a release created by a lost
+// response is read back instead of failing with a conflict
+func TestGitLabReleaserRetry(t *testing.T) {
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "job", r.Header.Get("JOB-TOKEN"))
+		requests = append(requests, r.Method+" "+r.URL.EscapedPath())
+		switch len(requests) {
+		case 1:
+			w.WriteHeader(http.StatusBadGateway)
+		case 2:
+			w.WriteHeader(http.StatusConflict)
+			_, _ = w.Write([]byte(`{"message": "Release already exists"}`))
+		default:
+			_, _ = w.Write([]byte(`{"_links": {"self": "https://gitlab.example.com/app/-/releases/v1.3.0"}}`))
+		}
+	}))
+	defer server.Close()
+
+	var retries []int
+	g := &GitLabReleaser{
+		APIURL: server.URL, ProjectID: "42", Token: "job", JobToken: true, Delay: time.Millisecond,
+		OnRetry: func(attempt int, err error) { retries = append(retries, attempt) },
+	}
+	url, err := g.CreateRelease(context.Background(), ReleaseRequest{Tag: "v1.3.0"})
+	assert.NoError(t, err)
+	assert.Equal(t, "https://gitlab.example.com/app/-/releases/v1.3.0", url)
+	assert.Equal(t, []int{1}, retries)
+	assert.Equal(t, []string{"POST /projects/42/releases", "POST /projects/42/releases", "GET /projects/42/releases/v1.3.0"}, requests)
+}
+
+// TestGitLabReleaserError tests that client errors are not retried and carry the validation message
+func TestGitLabReleaserError(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		_, _ = w.Write([]byte(`{"message": {"milestones": ["not found"]}}`))
+	}))
+	defer server.Close()
+
+	g := &GitLabReleaser{APIURL: server.URL, ProjectID: "42", Token: "secret", Delay: time.Millisecond}
+	_, err := g.CreateRelease(context.Background(), ReleaseRequest{Tag: "v1.3.0", Milestones: []string{"2.0"}})
+	var forgeErr ForgeError
+	if assert.ErrorAs(t, err, &forgeErr) {
+		assert.Equal(t, `{"milestones": ["not found"]}`, forgeErr.Message)
+	}
+	assert.Equal(t, 1, calls)
+}
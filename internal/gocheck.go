@@ -0,0 +1,194 @@
+package internal
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// GoCheck is a single release hygiene check for Go modules.
+type GoCheck struct {
+	// ID identifies the check in machine-readable output.
+	ID string
+	// Name is printed when the check passes.
+	Name string
+	Run  func() error
+}
+
+// GoReplace is a replace directive found in go.mod.
+type GoReplace struct {
+	Old string
+	New string
+}
+
+// IsLocal reports whether the replacement points at a directory on disk rather than a module version.
+func (r GoReplace) IsLocal() bool {
+	return strings.HasPrefix(r.New, "./") || strings.HasPrefix(r.New, "../") || strings.HasPrefix(r.New, "/") ||
+		r.New == "." || r.New == ".." || filepath.IsAbs(r.New)
+}
+
+func (r GoReplace) String() string {
+	return fmt.Sprintf("%s => %s", r.Old, r.New)
+}
+
+// IsGoModule reports whether the directory contains a go.mod file.
+func IsGoModule(dir string) bool {
+	_, err := os.Stat(filepath.Join(dir, "go.mod"))
+	return err == nil
+}
+
+// GoReleaseChecks returns the Go hygiene checks applicable to the module in the given directory.
+func GoReleaseChecks(dir string) []GoCheck {
+	checks := []GoCheck{
+		{ID: "go_replace", Name: "no local replace directives", Run: func() error { return checkLocalReplaces(dir) }},
+		{ID: "go_mod_tidy", Name: "go.mod and go.sum are tidy", Run: func() error { return checkGoModTidy(dir) }},
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, "vendor", "modules.txt")); err == nil {
+		checks = append(checks, GoCheck{ID: "go_vendor", Name: "vendor/modules.txt is consistent", Run: func() error { return checkGoVendor(dir) }})
+	}
+
+	checks = append(checks,
+		GoCheck{ID: "go_vet", Name: "go vet passed", Run: func() error { return runGo(dir, "go vet failed", "vet", "./...") }},
+		GoCheck{ID: "go_build", Name: "go build passed", Run: func() error { return runGo(dir, "go build failed", "build", "./...") }},
+	)
+
+	return checks
+}
+
+// ParseGoReplaces extracts the replace directives from the contents of a go.mod file,
+// both the single-line form and the block form "replace ( ... )".
+func ParseGoReplaces(data []byte) []GoReplace {
+	var replaces []GoReplace
+	inBlock := false
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if i := strings.Index(line, "//"); i >= 0 {
+			line = line[:i]
+		}
+		line = strings.TrimSpace(line)
+
+		if inBlock {
+			if line == ")" {
+				inBlock = false
+				continue
+			}
+		} else {
+			rest, ok := replaceDirective(line)
+			if !ok {
+				continue
+			}
+			switch {
+			case rest == "(":
+				inBlock = true
+				continue
+			case strings.HasPrefix(rest, "(") && strings.HasSuffix(rest, ")"):
+				// a block on a single line: replace ( old => new )
+				rest = strings.TrimSpace(rest[1 : len(rest)-1])
+			}
+			line = rest
+		}
+
+		if r, ok := parseGoReplace(line); ok {
+			replaces = append(replaces, r)
+		}
+	}
+
+	return replaces
+}
+
+// replaceDirective returns what follows the replace keyword of a go.mod line.
+func replaceDirective(line string) (string, bool) {
+	rest, ok := strings.CutPrefix(line, "replace")
+	if !ok || rest == "" || (rest[0] != '(' && rest[0] != ' ' && rest[0] != '\t') {
+		return "", false
+	}
+	return strings.TrimSpace(rest), true
+}
+
+// parseGoReplace parses "old [version] => new [version]", dropping the optional versions,
+// only the module paths matter here.
+func parseGoReplace(line string) (GoReplace, bool) {
+	oldPath, newPath, ok := strings.Cut(line, "=>")
+	if !ok {
+		return GoReplace{}, false
+	}
+	oldFields := strings.Fields(oldPath)
+	newFields := strings.Fields(newPath)
+	if len(oldFields) == 0 || len(newFields) == 0 {
+		return GoReplace{}, false
+	}
+	return GoReplace{Old: unquoteModPath(oldFields[0]), New: unquoteModPath(newFields[0])}, true
+}
+
+// unquoteModPath removes the quotes go.mod allows around a path.
+func unquoteModPath(path string) string {
+	if unquoted, err := strconv.Unquote(path); err == nil {
+		return unquoted
+	}
+	return path
+}
+
+func checkLocalReplaces(dir string) error {
+	data, err := os.ReadFile(filepath.Join(dir, "go.mod"))
+	if err != nil {
+		return fmt.Errorf("failed to read go.mod: %w", err)
+	}
+
+	var local []string
+	for _, r := range ParseGoReplaces(data) {
+		if r.IsLocal() {
+			local = append(local, r.String())
+		}
+	}
+
+	if len(local) > 0 {
+		return fmt.Errorf("go.mod has local replace directives: %s", strings.Join(local, ", "))
+	}
+	return nil
+}
+
+func checkGoModTidy(dir string) error {
+	output, err := goCommand(dir, "mod", "tidy", "-diff").CombinedOutput()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && len(bytes.TrimSpace(output)) > 0 && bytes.HasPrefix(output, []byte("diff")) {
+			return fmt.Errorf("go.mod or go.sum is not tidy, run 'go mod tidy'")
+		}
+		return fmt.Errorf("go mod tidy failed: %v - %s", err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+func checkGoVendor(dir string) error {
+	output, err := goCommand(dir, "list", "-mod=vendor", "./...").CombinedOutput()
+	if err != nil {
+		if bytes.Contains(output, []byte("inconsistent vendoring")) {
+			return fmt.Errorf("vendor/modules.txt is inconsistent with go.mod, run 'go mod vendor'")
+		}
+		return fmt.Errorf("failed to check vendor directory: %v - %s", err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+func runGo(dir, failure string, args ...string) error {
+	output, err := goCommand(dir, args...).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("%s: %v - %s", failure, err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+func goCommand(dir string, args ...string) *exec.Cmd {
+	cmd := exec.Command("go", args...)
+	cmd.Dir = dir
+	return cmd
+}
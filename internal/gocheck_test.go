@@ -0,0 +1,74 @@
+package internal
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestParseGoReplaces tests parsing of single-line and block replace directives
+func TestParseGoReplaces(t *testing.T) {
+	gomod := []byte(`module example.com/app
+
+go 1.24.0
+
+require example.com/lib v1.2.3
+
+replace example.com/lib => ../lib // local checkout
+
+replace (
+	example.com/other v1.0.0 => example.com/fork v1.0.1
+	example.com/abs => /src/abs
+)
+`)
+
+	replaces := ParseGoReplaces(gomod)
+
+	assert.Equal(t, []GoReplace{
+		{Old: "example.com/lib", New: "../lib"},
+		{Old: "example.com/other", New: "example.com/fork"},
+		{Old: "example.com/abs", New: "/src/abs"},
+	}, replaces)
+	assert.True(t, replaces[0].IsLocal())
+	assert.False(t, replaces[1].IsLocal())
+	assert.True(t, replaces[2].IsLocal())
+
+	replaces = ParseGoReplaces([]byte("module example.com/app\n\nreplace(\n\t\"example.com/lib\" => ../lib\n)\n\nreplace ( example.com/one => ./one )\nreplacement example.com/x => ../x\n"))
+	assert.Equal(t, []GoReplace{
+		{Old: "example.com/lib", New: "../lib"},
+		{Old: "example.com/one", New: "./one"},
+	}, replaces)
+}
+
+// goModule writes a module without dependencies to a temporary directory and returns it.
+func goModule(t *testing.T, gomod string) string {
+	t.Setenv("GOFLAGS", "-mod=mod")
+	t.Setenv("GOPROXY", "off")
+	t.Setenv("GOTOOLCHAIN", "local")
+
+	dir := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "go.mod"), []byte(gomod), 0o644))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "main.go"), []byte("package main\n\nfunc main() {}\n"), 0o644))
+	return dir
+}
+
+// TestCheckLocalReplaces tests that local replacements in either form fail the check
+func TestCheckLocalReplaces(t *testing.T) {
+	dir := goModule(t, "module example.com/app\n\ngo 1.24\n\nreplace example.com/lib v1.0.0 => example.com/fork v1.0.1\n")
+	assert.NoError(t, checkLocalReplaces(dir))
+
+	dir = goModule(t, "module example.com/app\n\ngo 1.24\n\nreplace (\n\texample.com/lib => ../lib\n)\n")
+	assert.ErrorContains(t, checkLocalReplaces(dir), "example.com/lib => ../lib")
+}
+
+// TestCheckGoModTidy tests that a stale go.sum entry fails the tidy check
+func TestCheckGoModTidy(t *testing.T) {
+	dir := goModule(t, "module example.com/app\n\ngo 1.24\n")
+	assert.NoError(t, checkGoModTidy(dir))
+
+	sum := "example.com/lib v1.2.3/go.mod h1:47DEQpj8HBSa+/TImW+5JCeuQeRkm5NMpJWZG3hSuFU=\n"
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "go.sum"), []byte(sum), 0o644))
+	assert.ErrorContains(t, checkGoModTidy(dir), "not tidy")
+}
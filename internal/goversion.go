@@ -0,0 +1,47 @@
+package internal
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+)
+
+// locateGo finds the package-level string var or const named ident in Go source
+// and returns the byte range of its value without the quotes.
+func locateGo(data []byte, ident string) (int, int, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "", data, parser.ParseComments)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid go source: %w", err)
+	}
+
+	for _, decl := range file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || (gen.Tok != token.CONST && gen.Tok != token.VAR) {
+			continue
+		}
+
+		for _, spec := range gen.Specs {
+			vs := spec.(*ast.ValueSpec)
+			for i, name := range vs.Names {
+				if name.Name != ident {
+					continue
+				}
+
+				if i >= len(vs.Values) {
+					return 0, 0, fmt.Errorf("%s %s has no value", gen.Tok, ident)
+				}
+				lit, ok := vs.Values[i].(*ast.BasicLit)
+				if !ok || lit.Kind != token.STRING {
+					return 0, 0, fmt.Errorf("%s %s is not a string literal", gen.Tok, ident)
+				}
+
+				start := fset.Position(lit.Pos()).Offset + 1
+				return start, start + len(lit.Value) - 2, nil
+			}
+		}
+	}
+
+	return 0, 0, fmt.Errorf("package-level var or const %s not found", ident)
+}
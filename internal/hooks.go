@@ -0,0 +1,110 @@
+package internal
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"runtime"
+)
+
+// HookPhase names a point in the bump lifecycle at which hooks are executed.
+type HookPhase string
+
+const (
+	PreBump  HookPhase = "pre-bump"
+	PostTag  HookPhase = "post-tag"
+	PostPush HookPhase = "post-push"
+	PostUndo HookPhase = "post-undo"
+)
+
+// HookEnv describes the release being processed and is exposed to hooks as BUMP_* environment variables.
+type HookEnv struct {
+	PreviousVersion string
+	NewVersion      string
+	Tag             string
+	Commit          string
+	Remote          string
+}
+
+// HookError is returned when a hook command exits unsuccessfully.
+type HookError struct {
+	Phase   HookPhase
+	Command string
+	Err     error
+}
+
+func (e HookError) Error() string {
+	return fmt.Sprintf("%s hook '%s' failed: %v", e.Phase, e.Command, e.Err)
+}
+
+func (e HookError) Unwrap() error {
+	return e.Err
+}
+
+// Commands returns the hook commands configured for the given phase.
+func (h HooksConfig) Commands(phase HookPhase) []string {
+	switch phase {
+	case PreBump:
+		return h.PreBump
+	case PostTag:
+		return h.PostTag
+	case PostPush:
+		return h.PostPush
+	case PostUndo:
+		return h.PostUndo
+	default:
+		return nil
+	}
+}
+
+// environ returns the current process environment extended with the BUMP_* variables.
+func (e HookEnv) environ() []string {
+	return append(os.Environ(),
+		"BUMP_PREVIOUS_VERSION="+e.PreviousVersion,
+		"BUMP_NEW_VERSION="+e.NewVersion,
+		"BUMP_TAG="+e.Tag,
+		"BUMP_COMMIT="+e.Commit,
+		"BUMP_REMOTE="+e.Remote,
+	)
+}
+
+// Expand replaces $BUMP_* variables, and any other environment variable, in s.
+func (e HookEnv) Expand(s string) string {
+	vars := map[string]string{
+		"BUMP_PREVIOUS_VERSION": e.PreviousVersion,
+		"BUMP_NEW_VERSION":      e.NewVersion,
+		"BUMP_TAG":              e.Tag,
+		"BUMP_COMMIT":           e.Commit,
+		"BUMP_REMOTE":           e.Remote,
+	}
+	return os.Expand(s, func(name string) string {
+		if v, ok := vars[name]; ok {
+			return v
+		}
+		return os.Getenv(name)
+	})
+}
+
+// RunHooks executes the commands configured for the phase one by one through the system shell,
+// sending their standard output to out. It stops at the first failing command and returns a HookError describing it.
+func RunHooks(hooks HooksConfig, phase HookPhase, env HookEnv, out io.Writer) error {
+	for _, command := range hooks.Commands(phase) {
+		cmd := shellCommand(command)
+		cmd.Env = env.environ()
+		cmd.Stdout = out
+		cmd.Stderr = os.Stderr
+
+		if err := cmd.Run(); err != nil {
+			return HookError{Phase: phase, Command: command, Err: err}
+		}
+	}
+	return nil
+}
+
+func shellCommand(command string) *exec.Cmd {
+	if runtime.GOOS == "windows" {
+		return exec.Command("cmd", "/C", command)
+	}
+	return exec.Command("sh", "-c", command)
+}
@@ -0,0 +1,86 @@
+package internal
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestRunHooks tests that hooks receive the BUMP_* variables and that failures stop the phase
+func TestRunHooks(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("hooks tests rely on sh")
+	}
+
+	out := filepath.Join(t.TempDir(), "env.txt")
+	env := HookEnv{
+		PreviousVersion: "1.2.3",
+		NewVersion:      "1.2.4",
+		Tag:             "v1.2.4",
+		Commit:          "abc123",
+		Remote:          "origin",
+	}
+
+	testCases := []struct {
+		name        string
+		hooks       HooksConfig
+		phase       HookPhase
+		expectFile  string
+		expectError bool
+	}{
+		{
+			name:       "Environment is passed",
+			hooks:      HooksConfig{PostTag: []string{`echo "$BUMP_PREVIOUS_VERSION $BUMP_NEW_VERSION $BUMP_TAG $BUMP_COMMIT $BUMP_REMOTE" > ` + out}},
+			phase:      PostTag,
+			expectFile: "1.2.3 1.2.4 v1.2.4 abc123 origin\n",
+		},
+		{
+			name:        "Failing hook stops the phase",
+			hooks:       HooksConfig{PreBump: []string{"exit 3", "echo unreachable > " + out}},
+			phase:       PreBump,
+			expectError: true,
+		},
+		{
+			name:  "Other phases are not run",
+			hooks: HooksConfig{PostPush: []string{"exit 1"}},
+			phase: PostUndo,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_ = os.Remove(out)
+
+			err := RunHooks(tc.hooks, tc.phase, env, io.Discard)
+
+			if tc.expectError {
+				var hookErr HookError
+				assert.True(t, errors.As(err, &hookErr))
+				assert.Equal(t, tc.phase, hookErr.Phase)
+				assert.NoFileExists(t, out)
+				return
+			}
+
+			assert.NoError(t, err)
+			if tc.expectFile != "" {
+				data, err := os.ReadFile(out)
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expectFile, string(data))
+			}
+		})
+	}
+}
+
+// TestHookEnvExpand tests that BUMP_* variables take precedence over the environment
+func TestHookEnvExpand(t *testing.T) {
+	t.Setenv("BUMP_TAG", "ignored")
+	t.Setenv("REGISTRY", "https://example.com")
+
+	env := HookEnv{NewVersion: "1.2.4", Tag: "v1.2.4"}
+	assert.Equal(t, "https://example.com/app/v1.2.4/app_1.2.4.tar.gz", env.Expand("${REGISTRY}/app/$BUMP_TAG/app_${BUMP_NEW_VERSION}.tar.gz"))
+}
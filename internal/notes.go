@@ -0,0 +1,240 @@
+package internal
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// UnreleasedVersion is the version name of the changes made since the latest tag.
+const UnreleasedVersion = "Unreleased"
+
+// DefaultChangelogTemplate renders a release in the Keep a Changelog layout with commits grouped by type.
+const DefaultChangelogTemplate = `## [{{.Version}}]{{if .Date}} - {{.Date}}{{end}}
+{{- if .Breaking}}
+
+### BREAKING CHANGES
+{{- range .Breaking}}
+- {{if .Scope}}**{{.Scope}}:** {{end}}{{.Description}}{{if .BreakingNote}}: {{.BreakingNote}}{{end}}
+{{- end}}
+{{- end}}
+{{- range .Groups}}
+
+### {{.Title}}
+{{- range .Commits}}
+- {{if .Scope}}**{{.Scope}}:** {{end}}{{.Description}} ({{.ShortHash}})
+{{- end}}
+{{- end}}
+`
+
+// ReleaseNotesTemplate renders the body of a release page on a forge: the commits grouped by type without a version heading.
+const ReleaseNotesTemplate = `{{- if .Breaking}}### BREAKING CHANGES
+{{range .Breaking}}
+- {{if .Scope}}**{{.Scope}}:** {{end}}{{.Description}}{{if .BreakingNote}}: {{.BreakingNote}}{{end}}
+{{- end}}
+{{end}}
+{{- range $i, $g := .Groups}}{{if or $i $.Breaking}}
+{{end}}### {{.Title}}
+{{range .Commits}}
+- {{if .Scope}}**{{.Scope}}:** {{end}}{{.Description}} ({{.ShortHash}})
+{{- end}}
+{{end}}
+{{- if .PreviousTag}}
+**Full changelog:** {{.PreviousTag}}...{{.Tag}}
+{{end}}`
+
+// Release is a single changelog section with the commits made since the previous tag.
+type Release struct {
+	Version     string
+	Tag         string
+	PreviousTag string
+	Date        string
+	Commits     []Commit
+	Groups      []CommitGroup
+	Breaking    []Commit
+}
+
+// CommitsBetween returns the non-merge commits reachable from to but not from from, newest first.
+// An empty from returns the whole history of to.
+func CommitsBetween(from, to string) ([]Commit, error) {
+	rev := to
+	if from != "" {
+		rev = from + ".." + to
+	}
+
+	cmd := exec.Command("git", "log", "--no-merges", "--format=%H%x1f%s%x1f%b%x1e", rev)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return nil, fmt.Errorf("error reading git log: %v - %s", err, string(output))
+	}
+
+	var commits []Commit
+	for _, record := range strings.Split(string(output), "\x1e") {
+		fields := strings.Split(strings.TrimLeft(record, "\n"), "\x1f")
+		if len(fields) < 3 {
+			continue
+		}
+		commits = append(commits, ParseCommit(fields[0], fields[1], fields[2]))
+	}
+
+	return commits, nil
+}
+
+// NewRelease collects the commits between previousTag and to into a release.
+func NewRelease(version, tag, previousTag, to string, date time.Time) (Release, error) {
+	commits, err := CommitsBetween(previousTag, to)
+	if err != nil {
+		return Release{}, err
+	}
+
+	r := Release{
+		Version:     version,
+		Tag:         tag,
+		PreviousTag: previousTag,
+		Commits:     commits,
+	}
+	if !date.IsZero() {
+		r.Date = date.Format(time.DateOnly)
+	}
+	r.Groups, r.Breaking = GroupCommits(commits)
+
+	return r, nil
+}
+
+// ReleaseHistory builds a release for every semver tag, newest first.
+// The commits made after the latest tag are returned as an unreleased release on top.
+func ReleaseHistory() ([]Release, error) {
+	tags, err := SemverTags()
+	if err != nil {
+		return nil, err
+	}
+
+	var releases []Release
+	for i, tag := range tags {
+		previous := ""
+		if i > 0 {
+			previous = tags[i-1]
+		}
+
+		date, err := TagDate(tag)
+		if err != nil {
+			return nil, err
+		}
+
+		r, err := NewRelease(strings.TrimPrefix(tag, "v"), tag, previous, tag, date)
+		if err != nil {
+			return nil, err
+		}
+		releases = append(releases, r)
+	}
+
+	latest := ""
+	if len(tags) > 0 {
+		latest = tags[len(tags)-1]
+	}
+	unreleased, err := NewRelease(UnreleasedVersion, "", latest, "HEAD", time.Time{})
+	if err != nil {
+		return nil, err
+	}
+	if len(unreleased.Commits) > 0 {
+		releases = append(releases, unreleased)
+	}
+
+	// Newest first, like in a changelog
+	for i, j := 0, len(releases)-1; i < j; i, j = i+1, j-1 {
+		releases[i], releases[j] = releases[j], releases[i]
+	}
+
+	return releases, nil
+}
+
+// RenderReleases renders every release through the template and joins them with blank lines.
+// An empty template selects DefaultChangelogTemplate.
+func RenderReleases(tmpl string, releases []Release) ([]byte, error) {
+	if tmpl == "" {
+		tmpl = DefaultChangelogTemplate
+	}
+
+	t, err := template.New("changelog").Parse(tmpl)
+	if err != nil {
+		return nil, fmt.Errorf("invalid changelog template: %w", err)
+	}
+
+	var buf bytes.Buffer
+	for i, r := range releases {
+		if i > 0 {
+			buf.WriteString("\n")
+		}
+		if err := t.Execute(&buf, r); err != nil {
+			return nil, fmt.Errorf("failed to render changelog: %w", err)
+		}
+	}
+
+	return buf.Bytes(), nil
+}
+
+// ReplaceReleases replaces every release section of an existing changelog with the rendered sections.
+// The title and introduction above the first release and the link definitions at the end are kept.
+func ReplaceReleases(existing, sections []byte) []byte {
+	if len(bytes.TrimSpace(existing)) == 0 {
+		return append([]byte("# Changelog\n\n"), sections...)
+	}
+
+	lines := strings.SplitAfter(string(existing), "\n")
+	first := len(lines)
+	for i, line := range lines {
+		if strings.HasPrefix(line, "## ") {
+			first = i
+			break
+		}
+	}
+	head := strings.TrimRight(strings.Join(lines[:first], ""), "\n") + "\n\n"
+
+	footer := len(lines)
+	for i := len(lines) - 1; i >= first; i-- {
+		line := strings.TrimSpace(lines[i])
+		if line != "" && !linkReferenceRe.MatchString(line) {
+			break
+		}
+		if line != "" {
+			footer = i
+		}
+	}
+	if footer == len(lines) {
+		return []byte(head + string(sections))
+	}
+	return []byte(head + strings.TrimRight(string(sections), "\n") + "\n\n" + strings.Join(lines[footer:], ""))
+}
+
+// PrependChangelog inserts a rendered section above the first release of an existing changelog,
+// keeping its title, introduction and [Unreleased] section on top. A rendered [Unreleased] section
+// replaces the existing one instead.
+func PrependChangelog(existing, section []byte) []byte {
+	if len(bytes.TrimSpace(existing)) == 0 {
+		return append([]byte("# Changelog\n\n"), section...)
+	}
+
+	lines := strings.SplitAfter(string(existing), "\n")
+	if unreleasedHeaderRe.Match(section) {
+		if start, end, err := unreleasedSection(lines); err == nil {
+			head := strings.Join(lines[:start-1], "")
+			tail := strings.Join(lines[end:], "")
+			if tail != "" {
+				return []byte(head + string(section) + "\n" + tail)
+			}
+			return []byte(head + string(section))
+		}
+	}
+
+	for i, line := range lines {
+		if strings.HasPrefix(line, "## ") && !unreleasedHeaderRe.MatchString(line) {
+			head := strings.Join(lines[:i], "")
+			return []byte(head + string(section) + "\n" + strings.Join(lines[i:], ""))
+		}
+	}
+
+	return []byte(strings.TrimRight(string(existing), "\n") + "\n\n" + string(section))
+}
@@ -0,0 +1,152 @@
+package internal
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestReleaseNotesTemplate tests the body rendered for a forge release page
+func TestReleaseNotesTemplate(t *testing.T) {
+	commits := []Commit{
+		ParseCommit("1111111111", "feat(api)!: drop v1 endpoints", "BREAKING CHANGE: use /v2"),
+		ParseCommit("2222222222", "fix: handle empty tags", ""),
+		ParseCommit("3333333333", "feat: add sync", ""),
+	}
+	r := Release{Version: "2.0.0", Tag: "v2.0.0", PreviousTag: "v1.4.0", Commits: commits}
+	r.Groups, r.Breaking = GroupCommits(commits)
+
+	out, err := RenderReleases(ReleaseNotesTemplate, []Release{r})
+	assert.NoError(t, err)
+	assert.Equal(t, "### BREAKING CHANGES\n\n- **api:** drop v1 endpoints: use /v2\n\n"+
+		"### Features\n\n- **api:** drop v1 endpoints (1111111)\n- add sync (3333333)\n\n"+
+		"### Bug Fixes\n\n- handle empty tags (2222222)\n\n"+
+		"**Full changelog:** v1.4.0...v2.0.0\n", string(out))
+
+	out, err = RenderReleases(ReleaseNotesTemplate, []Release{{Version: "0.0.1", Tag: "v0.0.1"}})
+	assert.NoError(t, err)
+	assert.Equal(t, "", string(out))
+}
+
+// TestRenderReleases tests the default template, custom templates and the separation of several releases
+func TestRenderReleases(t *testing.T) {
+	commits := []Commit{
+		ParseCommit("1111111111", "feat(cli): add sync", ""),
+		ParseCommit("2222222222", "fix: handle empty tags", ""),
+	}
+	r := Release{Version: "1.3.0", Tag: "v1.3.0", Date: "2025-01-02", Commits: commits}
+	r.Groups, r.Breaking = GroupCommits(commits)
+
+	out, err := RenderReleases("", []Release{r, {Version: "1.2.0", Date: "2024-12-01"}})
+	assert.NoError(t, err)
+	assert.Equal(t, "## [1.3.0] - 2025-01-02\n\n### Features\n- **cli:** add sync (1111111)\n\n"+
+		"### Bug Fixes\n- handle empty tags (2222222)\n\n## [1.2.0] - 2024-12-01\n", string(out))
+
+	out, err = RenderReleases("{{.Tag}}: {{len .Commits}} commits\n", []Release{r})
+	assert.NoError(t, err)
+	assert.Equal(t, "v1.3.0: 2 commits\n", string(out))
+
+	_, err = RenderReleases("{{.Tag", []Release{r})
+	assert.Error(t, err)
+}
+
+// TestReplaceReleases tests that regenerating the releases keeps the introduction and the link definitions
+func TestReplaceReleases(t *testing.T) {
+	sections := "## [1.1.0]\n- new\n\n## [1.0.0]\n- first\n"
+
+	testCases := []struct {
+		name     string
+		existing string
+		expected string
+	}{
+		{
+			name:     "Empty file",
+			existing: "",
+			expected: "# Changelog\n\n" + sections,
+		},
+		{
+			name:     "No releases",
+			existing: "# Changelog\n\nAll notable changes.\n",
+			expected: "# Changelog\n\nAll notable changes.\n\n" + sections,
+		},
+		{
+			name:     "Releases replaced",
+			existing: "# Changelog\n\nAll notable changes.\n\n## [Unreleased]\n- stale\n\n## [1.0.0]\n- hand written\n",
+			expected: "# Changelog\n\nAll notable changes.\n\n" + sections,
+		},
+		{
+			name: "Link definitions kept",
+			existing: "# Changelog\n\n## [1.0.0]\n- old\n\n" +
+				"[1.1.0]: https://example.com/compare/v1.0.0...v1.1.0\n[1.0.0]: https://example.com/tag/v1.0.0\n",
+			expected: "# Changelog\n\n" + sections + "\n" +
+				"[1.1.0]: https://example.com/compare/v1.0.0...v1.1.0\n[1.0.0]: https://example.com/tag/v1.0.0\n",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			out := ReplaceReleases([]byte(tc.existing), []byte(sections))
+			assert.Equal(t, tc.expected, string(out))
+		})
+	}
+}
+
+// TestPrependChangelog tests where a rendered section is placed in an existing changelog
+func TestPrependChangelog(t *testing.T) {
+	unreleased := "## [Unreleased]\n\n### Features\n- add sync (1111111)\n"
+
+	testCases := []struct {
+		name     string
+		existing string
+		section  string
+		expected string
+	}{
+		{
+			name:     "Empty file",
+			existing: "",
+			section:  "## [1.0.0]\n",
+			expected: "# Changelog\n\n## [1.0.0]\n",
+		},
+		{
+			name:     "No releases",
+			existing: "# Changelog\n\nAll notable changes.\n",
+			section:  "## [1.0.0]\n",
+			expected: "# Changelog\n\nAll notable changes.\n\n## [1.0.0]\n",
+		},
+		{
+			name:     "Release below the existing Unreleased section",
+			existing: "# Changelog\n\n## [Unreleased]\n\n- manual entry\n\n## [0.9.0]\n- old\n",
+			section:  "## [1.0.0]\n",
+			expected: "# Changelog\n\n## [Unreleased]\n\n- manual entry\n\n## [1.0.0]\n\n## [0.9.0]\n- old\n",
+		},
+		{
+			name:     "Unreleased replaces the existing Unreleased section",
+			existing: "# Changelog\n\n## [Unreleased]\n\n- stale entry\n\n## [0.9.0]\n- old\n",
+			section:  unreleased,
+			expected: "# Changelog\n\n" + unreleased + "\n## [0.9.0]\n- old\n",
+		},
+		{
+			name:     "Unreleased replaces the last section",
+			existing: "# Changelog\n\n## [Unreleased]\n- stale entry\n",
+			section:  unreleased,
+			expected: "# Changelog\n\n" + unreleased,
+		},
+		{
+			name:     "Unreleased without an existing Unreleased section",
+			existing: "# Changelog\n\n## [0.9.0]\n- old\n",
+			section:  unreleased,
+			expected: "# Changelog\n\n" + unreleased + "\n## [0.9.0]\n- old\n",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			out := PrependChangelog([]byte(tc.existing), []byte(tc.section))
+			assert.Equal(t, tc.expected, string(out))
+			if tc.section == unreleased {
+				// writing the unreleased changes again keeps a single section
+				assert.Equal(t, tc.expected, string(PrependChangelog(out, []byte(tc.section))))
+			}
+		})
+	}
+}
@@ -0,0 +1,65 @@
+package internal
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SupersededPrereleases returns the pre-release tags for which a higher final release exists,
+// e.g. v1.2.0-rc.1 once v1.2.0 is released. Final releases are never returned.
+// The keepLast highest pre-releases of every version are kept, and with a non-zero before only
+// tags dated before it are returned. The tags must be ordered by version as returned by ListTags.
+func SupersededPrereleases(tags []TagInfo, keepLast int, before time.Time) []TagInfo {
+	var latestFinal *TagInfo
+	for i := range tags {
+		if tags[i].Version.Prerelease() == "" {
+			latestFinal = &tags[i]
+		}
+	}
+	if latestFinal == nil {
+		return nil
+	}
+
+	kept := make(map[string]int)
+	var superseded []TagInfo
+	for i := len(tags) - 1; i >= 0; i-- {
+		t := tags[i]
+		if t.Version.Prerelease() == "" || !latestFinal.Version.GreaterThan(t.Version) {
+			continue
+		}
+
+		core := fmt.Sprintf("%d.%d.%d", t.Version.Major(), t.Version.Minor(), t.Version.Patch())
+		if kept[core] < keepLast {
+			kept[core]++
+			continue
+		}
+		if !before.IsZero() && !t.Date.Before(before) {
+			continue
+		}
+		superseded = append([]TagInfo{t}, superseded...)
+	}
+
+	return superseded
+}
+
+// ParseAge parses an age such as 30d, 2w or any time.ParseDuration value like 36h.
+func ParseAge(s string) (time.Duration, error) {
+	units := map[string]time.Duration{"d": 24 * time.Hour, "w": 7 * 24 * time.Hour}
+	for suffix, unit := range units {
+		if n, ok := strings.CutSuffix(s, suffix); ok {
+			count, err := strconv.Atoi(n)
+			if err != nil || count < 0 {
+				return 0, fmt.Errorf("invalid age '%s'", s)
+			}
+			return time.Duration(count) * unit, nil
+		}
+	}
+
+	d, err := time.ParseDuration(s)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("invalid age '%s', use e.g. 30d, 2w or 36h", s)
+	}
+	return d, nil
+}
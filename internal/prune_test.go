@@ -0,0 +1,57 @@
+package internal
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/stretchr/testify/assert"
+)
+
+func pruneTags(now time.Time, names ...string) []TagInfo {
+	tags := make([]TagInfo, len(names))
+	for i, name := range names {
+		// the lower the version, the older the tag
+		tags[i] = TagInfo{Name: name, Version: semver.MustParse(name), Date: now.Add(-time.Duration(len(names)-i) * 24 * time.Hour)}
+	}
+	return tags
+}
+
+func tagNames(tags []TagInfo) []string {
+	names := make([]string, len(tags))
+	for i, t := range tags {
+		names[i] = t.Name
+	}
+	return names
+}
+
+// TestSupersededPrereleases tests that only pre-releases below the latest final release are pruned
+func TestSupersededPrereleases(t *testing.T) {
+	now := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	tags := pruneTags(now, "v1.0.0-rc.1", "v1.0.0-rc.2", "v1.0.0", "v1.1.0-beta.1", "v1.1.0-dev", "v1.1.0-rc.1", "v1.1.0", "v1.2.0-rc.1")
+
+	assert.Equal(t, []string{"v1.0.0-rc.1", "v1.0.0-rc.2", "v1.1.0-beta.1", "v1.1.0-dev", "v1.1.0-rc.1"},
+		tagNames(SupersededPrereleases(tags, 0, time.Time{})))
+
+	assert.Equal(t, []string{"v1.0.0-rc.1", "v1.1.0-beta.1", "v1.1.0-dev"},
+		tagNames(SupersededPrereleases(tags, 1, time.Time{})))
+
+	assert.Equal(t, []string{"v1.0.0-rc.1", "v1.0.0-rc.2"},
+		tagNames(SupersededPrereleases(tags, 0, now.Add(-5*24*time.Hour))))
+
+	assert.Empty(t, SupersededPrereleases(pruneTags(now, "v1.0.0-rc.1", "v1.0.0-rc.2"), 0, time.Time{}))
+}
+
+// TestParseAge tests day and week suffixes next to Go durations
+func TestParseAge(t *testing.T) {
+	for s, want := range map[string]time.Duration{"30d": 30 * 24 * time.Hour, "2w": 14 * 24 * time.Hour, "36h": 36 * time.Hour} {
+		d, err := ParseAge(s)
+		assert.NoError(t, err)
+		assert.Equal(t, want, d, s)
+	}
+
+	for _, s := range []string{"d", "-1d", "soon"} {
+		_, err := ParseAge(s)
+		assert.Error(t, err, s)
+	}
+}
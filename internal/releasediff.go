@@ -0,0 +1,261 @@
+package internal
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os/exec"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+)
+
+// emptyTree is the hash of the empty git tree, used to diff against the beginning of the history.
+const emptyTree = "4b825dc642cb6eb9a060e54bf8d69288fbee4904"
+
+// FileStat is a file changed between two revisions.
+type FileStat struct {
+	Path    string
+	Added   int
+	Deleted int
+	Binary  bool
+}
+
+// DirStat summarises the changed files of a directory. Package is the import path
+// of the directory when it is part of the Go module and has changed Go files.
+type DirStat struct {
+	Dir     string
+	Package string
+	Files   int
+	Added   int
+	Deleted int
+}
+
+// ModuleChangeKind tells how a go.mod requirement changed.
+type ModuleChangeKind string
+
+const (
+	ModuleAdded      ModuleChangeKind = "added"
+	ModuleRemoved    ModuleChangeKind = "removed"
+	ModuleUpgraded   ModuleChangeKind = "upgraded"
+	ModuleDowngraded ModuleChangeKind = "downgraded"
+)
+
+// ModuleChange is a requirement of go.mod that differs between two revisions.
+type ModuleChange struct {
+	Path string
+	From string
+	To   string
+	Kind ModuleChangeKind
+}
+
+// ReleaseDiff is everything that changed between two revisions.
+type ReleaseDiff struct {
+	From     string
+	To       string
+	Commits  []Commit
+	Groups   []CommitGroup
+	Breaking []Commit
+	Files    []FileStat
+	Dirs     []DirStat
+	Modules  []ModuleChange
+}
+
+// DiffReleases collects the commits, changed files and go.mod requirement changes between two revisions.
+// An empty from compares with the beginning of the history.
+func DiffReleases(from, to string) (ReleaseDiff, error) {
+	d := ReleaseDiff{From: from, To: to}
+
+	var err error
+	d.Commits, err = CommitsBetween(from, to)
+	if err != nil {
+		return d, err
+	}
+	d.Groups, d.Breaking = GroupCommits(d.Commits)
+
+	d.Files, err = ChangedFiles(from, to)
+	if err != nil {
+		return d, err
+	}
+
+	newMod := fileAt(to, "go.mod")
+	d.Dirs = SummarizeDirs(d.Files, ParseGoModulePath(newMod))
+	if newMod != nil {
+		d.Modules = CompareRequires(ParseGoRequires(fileAt(from, "go.mod")), ParseGoRequires(newMod))
+	}
+
+	return d, nil
+}
+
+// ChangedFiles returns the files changed between two revisions with their added and deleted lines.
+func ChangedFiles(from, to string) ([]FileStat, error) {
+	if from == "" {
+		from = emptyTree
+	}
+
+	cmd := exec.Command("git", "diff", "--numstat", "--no-renames", from, to)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return nil, fmt.Errorf("error reading git diff: %v - %s", err, string(output))
+	}
+
+	var files []FileStat
+	for _, line := range strings.Split(string(output), "\n") {
+		fields := strings.SplitN(line, "\t", 3)
+		if len(fields) != 3 {
+			continue
+		}
+
+		f := FileStat{Path: fields[2]}
+		if fields[0] == "-" {
+			f.Binary = true
+		} else {
+			f.Added, _ = strconv.Atoi(fields[0])
+			f.Deleted, _ = strconv.Atoi(fields[1])
+		}
+		files = append(files, f)
+	}
+
+	return files, nil
+}
+
+// SummarizeDirs groups changed files by their directory, ordered by path.
+func SummarizeDirs(files []FileStat, modulePath string) []DirStat {
+	byDir := make(map[string]*DirStat)
+	for _, f := range files {
+		dir := path.Dir(f.Path)
+		s, ok := byDir[dir]
+		if !ok {
+			s = &DirStat{Dir: dir}
+			byDir[dir] = s
+		}
+		s.Files++
+		s.Added += f.Added
+		s.Deleted += f.Deleted
+
+		if modulePath != "" && strings.HasSuffix(f.Path, ".go") && !isOutsideModule(dir) {
+			s.Package = modulePath
+			if dir != "." {
+				s.Package += "/" + dir
+			}
+		}
+	}
+
+	dirs := make([]DirStat, 0, len(byDir))
+	for _, dir := range sortedKeys(byDir) {
+		dirs = append(dirs, *byDir[dir])
+	}
+	return dirs
+}
+
+// isOutsideModule reports whether Go files in the directory do not belong to the module's packages.
+func isOutsideModule(dir string) bool {
+	for _, elem := range strings.Split(dir, "/") {
+		if elem == "vendor" || elem == "testdata" || (elem != "." && strings.HasPrefix(elem, ".")) || strings.HasPrefix(elem, "_") {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseGoModulePath returns the module path declared in a go.mod file.
+func ParseGoModulePath(data []byte) string {
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) >= 2 && fields[0] == "module" {
+			return strings.Trim(fields[1], `"`)
+		}
+	}
+	return ""
+}
+
+// ParseGoRequires returns the required module versions of a go.mod file by module path.
+func ParseGoRequires(data []byte) map[string]string {
+	requires := make(map[string]string)
+	inBlock := false
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if i := strings.Index(line, "//"); i >= 0 {
+			line = line[:i]
+		}
+		line = strings.TrimSpace(line)
+
+		switch {
+		case inBlock && line == ")":
+			inBlock = false
+			continue
+		case inBlock:
+		case line == "require (":
+			inBlock = true
+			continue
+		case strings.HasPrefix(line, "require "):
+			line = strings.TrimPrefix(line, "require ")
+		default:
+			continue
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) == 2 {
+			requires[strings.Trim(fields[0], `"`)] = fields[1]
+		}
+	}
+
+	return requires
+}
+
+// CompareRequires lists the requirements that were added, removed, upgraded or downgraded, ordered by module path.
+func CompareRequires(old, new map[string]string) []ModuleChange {
+	var changes []ModuleChange
+
+	for _, p := range sortedKeys(new) {
+		from, ok := old[p]
+		to := new[p]
+		switch {
+		case !ok:
+			changes = append(changes, ModuleChange{Path: p, To: to, Kind: ModuleAdded})
+		case from != to:
+			kind := ModuleUpgraded
+			if moduleVersionLess(to, from) {
+				kind = ModuleDowngraded
+			}
+			changes = append(changes, ModuleChange{Path: p, From: from, To: to, Kind: kind})
+		}
+	}
+	for _, p := range sortedKeys(old) {
+		if _, ok := new[p]; !ok {
+			changes = append(changes, ModuleChange{Path: p, From: old[p], Kind: ModuleRemoved})
+		}
+	}
+
+	slices.SortStableFunc(changes, func(a, b ModuleChange) int {
+		return strings.Compare(a.Path, b.Path)
+	})
+	return changes
+}
+
+func moduleVersionLess(a, b string) bool {
+	va, errA := semver.NewVersion(a)
+	vb, errB := semver.NewVersion(b)
+	if errA != nil || errB != nil {
+		return a < b
+	}
+	return va.LessThan(vb)
+}
+
+// fileAt returns the content of a file at a revision, or nil when it does not exist there.
+func fileAt(rev, file string) []byte {
+	if rev == "" {
+		return nil
+	}
+	output, err := exec.Command("git", "show", rev+":"+file).Output()
+	if err != nil {
+		return nil
+	}
+	return output
+}
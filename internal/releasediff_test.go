@@ -0,0 +1,63 @@
+package internal
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestCompareRequires tests that go.mod requirement changes are classified and ordered by module path
+func TestCompareRequires(t *testing.T) {
+	old := ParseGoRequires([]byte(`module example.com/app
+
+go 1.22
+
+require github.com/stretchr/testify v1.8.0
+
+require (
+	github.com/spf13/cobra v1.8.0
+	golang.org/x/sys v0.20.0 // indirect
+	gopkg.in/yaml.v3 v3.0.1
+)
+
+replace github.com/spf13/cobra => ../cobra
+`))
+	assert.Equal(t, map[string]string{
+		"github.com/stretchr/testify": "v1.8.0",
+		"github.com/spf13/cobra":      "v1.8.0",
+		"golang.org/x/sys":            "v0.20.0",
+		"gopkg.in/yaml.v3":            "v3.0.1",
+	}, old)
+
+	changes := CompareRequires(old, map[string]string{
+		"github.com/stretchr/testify":   "v1.9.0",
+		"github.com/spf13/cobra":        "v1.7.0",
+		"gopkg.in/yaml.v3":              "v3.0.1",
+		"github.com/Masterminds/semver": "v3.2.0",
+	})
+	assert.Equal(t, []ModuleChange{
+		{Path: "github.com/Masterminds/semver", To: "v3.2.0", Kind: ModuleAdded},
+		{Path: "github.com/spf13/cobra", From: "v1.8.0", To: "v1.7.0", Kind: ModuleDowngraded},
+		{Path: "github.com/stretchr/testify", From: "v1.8.0", To: "v1.9.0", Kind: ModuleUpgraded},
+		{Path: "golang.org/x/sys", From: "v0.20.0", Kind: ModuleRemoved},
+	}, changes)
+}
+
+// TestSummarizeDirs tests that changed files are grouped by directory and Go packages are recognised
+func TestSummarizeDirs(t *testing.T) {
+	files := []FileStat{
+		{Path: "main.go", Added: 3, Deleted: 1},
+		{Path: "cmd/bump.go", Added: 10},
+		{Path: "cmd/list.go", Added: 5, Deleted: 2},
+		{Path: "docs/usage.md", Added: 1},
+		{Path: "vendor/github.com/x/y/y.go", Added: 7},
+		{Path: "logo.png", Binary: true},
+	}
+
+	assert.Equal(t, []DirStat{
+		{Dir: ".", Package: "example.com/app", Files: 2, Added: 3, Deleted: 1},
+		{Dir: "cmd", Package: "example.com/app/cmd", Files: 2, Added: 15, Deleted: 2},
+		{Dir: "docs", Files: 1, Added: 1},
+		{Dir: "vendor/github.com/x/y", Files: 1, Added: 7},
+	}, SummarizeDirs(files, ParseGoModulePath([]byte("// app\nmodule example.com/app\n"))))
+}
@@ -0,0 +1,105 @@
+package internal
+
+import (
+	"fmt"
+	"os/exec"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Masterminds/semver/v3"
+)
+
+// TagInfo describes a semver tag of the repository.
+type TagInfo struct {
+	Name    string
+	Version *semver.Version
+	// Date is the tagger date of annotated tags and the commit date of lightweight tags
+	Date time.Time
+	// Author is the tagger of annotated tags and the commit author of lightweight tags
+	Author    string
+	Commit    string
+	Annotated bool
+	// CommitsSincePrevious counts the commits reachable from the tag but not from the previous version
+	CommitsSincePrevious int
+}
+
+// tagRefFormat lists the fields read for every tag, separated by tabs.
+const tagRefFormat = "%(refname:lstrip=2)%09%(objecttype)%09%(objectname)%09%(*objectname)%09%(creatordate:iso-strict)%09%(taggername)%09%(authorname)"
+
+// ListTags returns the semver tags of the repository ordered from the lowest to the highest version,
+// together with their dates, authors, target commits and the number of commits since the previous version.
+// With a prefix, e.g. "tools/" for a nested Go module, only tags starting with it are considered.
+func ListTags(prefix string) ([]TagInfo, error) {
+	cmd := exec.Command("git", "for-each-ref", "--format="+tagRefFormat, "refs/tags")
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("error listing git tags: %w", err)
+	}
+
+	tags, err := ParseTagRefs(string(output), prefix)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range tags {
+		args := []string{"rev-list", "--count", tags[i].Commit}
+		if i > 0 {
+			args = append(args, "^"+tags[i-1].Commit)
+		}
+		out, err := exec.Command("git", args...).Output()
+		if err != nil {
+			return nil, fmt.Errorf("error counting commits of %s: %w", tags[i].Name, err)
+		}
+		tags[i].CommitsSincePrevious, err = strconv.Atoi(strings.TrimSpace(string(out)))
+		if err != nil {
+			return nil, fmt.Errorf("error counting commits of %s: %w", tags[i].Name, err)
+		}
+	}
+
+	return tags, nil
+}
+
+// ParseTagRefs parses the output of `git for-each-ref` in tagRefFormat, keeping the tags whose name
+// without the prefix is a semver version. The tags are sorted by version.
+func ParseTagRefs(output, prefix string) ([]TagInfo, error) {
+	var tags []TagInfo
+	for _, line := range strings.Split(output, "\n") {
+		if line == "" {
+			continue
+		}
+
+		fields := strings.Split(line, "\t")
+		if len(fields) != 7 {
+			return nil, fmt.Errorf("unexpected tag line: %q", line)
+		}
+		name, objectType, object, peeled, date, tagger, author := fields[0], fields[1], fields[2], fields[3], fields[4], fields[5], fields[6]
+
+		if !strings.HasPrefix(name, prefix) {
+			continue
+		}
+		v, err := semver.NewVersion(strings.TrimPrefix(name, prefix))
+		if err != nil {
+			continue
+		}
+
+		t := TagInfo{Name: name, Version: v, Commit: object, Author: author, Annotated: objectType == "tag"}
+		if t.Annotated {
+			t.Commit = peeled
+			t.Author = tagger
+		}
+		if date != "" {
+			t.Date, err = time.Parse(time.RFC3339, date)
+			if err != nil {
+				return nil, fmt.Errorf("invalid date of tag %s: %w", name, err)
+			}
+		}
+		tags = append(tags, t)
+	}
+
+	slices.SortStableFunc(tags, func(a, b TagInfo) int {
+		return a.Version.Compare(b.Version)
+	})
+	return tags, nil
+}
@@ -0,0 +1,43 @@
+package internal
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestParseTagRefs tests that only semver tags are kept, sorted by version, with annotated tags peeled
+func TestParseTagRefs(t *testing.T) {
+	output := "v1.10.0\tcommit\taaa\t\t2025-03-01T10:00:00+01:00\t\tAlice\n" +
+		"v1.2.0\ttag\tttt\tbbb\t2025-01-01T10:00:00Z\tBob\t\n" +
+		"latest\tcommit\tccc\t\t2025-03-02T10:00:00Z\t\tAlice\n" +
+		"v1.10.0-rc.1\tcommit\tddd\t\t2025-02-01T10:00:00Z\t\tCarol\n" +
+		"tools/v0.1.0\tcommit\teee\t\t2025-02-01T10:00:00Z\t\tDave\n"
+
+	tags, err := ParseTagRefs(output, "")
+	assert.NoError(t, err)
+
+	names := make([]string, len(tags))
+	for i, tag := range tags {
+		names[i] = tag.Name
+	}
+	assert.Equal(t, []string{"v1.2.0", "v1.10.0-rc.1", "v1.10.0"}, names)
+
+	assert.True(t, tags[0].Annotated)
+	assert.Equal(t, "bbb", tags[0].Commit)
+	assert.Equal(t, "Bob", tags[0].Author)
+	assert.False(t, tags[2].Annotated)
+	assert.Equal(t, "aaa", tags[2].Commit)
+	assert.Equal(t, "Alice", tags[2].Author)
+	assert.Equal(t, 9, tags[2].Date.UTC().Hour())
+
+	tags, err = ParseTagRefs(output, "tools/")
+	assert.NoError(t, err)
+	if assert.Len(t, tags, 1) {
+		assert.Equal(t, "tools/v0.1.0", tags[0].Name)
+		assert.Equal(t, "0.1.0", tags[0].Version.String())
+	}
+
+	_, err = ParseTagRefs("v1.0.0\tcommit\n", "")
+	assert.Error(t, err)
+}
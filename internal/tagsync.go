@@ -0,0 +1,117 @@
+package internal
+
+import (
+	"fmt"
+	"os/exec"
+	"slices"
+	"strings"
+)
+
+// TagSyncState tells how a tag differs between the local repository and a remote.
+type TagSyncState string
+
+const (
+	TagLocalOnly  TagSyncState = "local-only"
+	TagRemoteOnly TagSyncState = "remote-only"
+	TagDiverged   TagSyncState = "diverged"
+)
+
+// TagSync is a tag that is missing on one side or points to different commits locally and on the remote.
+// Local and Remote are the commits the tag points to, empty when the tag is missing on that side.
+type TagSync struct {
+	Name   string
+	State  TagSyncState
+	Local  string
+	Remote string
+}
+
+// CompareRemoteTags lists the tags that differ between the local repository and the default remote.
+// Annotated tags are compared by the commit they point to.
+func (gs *GitState) CompareRemoteTags() ([]TagSync, error) {
+	localCmd := exec.Command("git", "show-ref", "--tags", "--dereference")
+	localOutput, err := localCmd.Output()
+	// show-ref exits with 1 when there are no tags
+	if err != nil && len(localOutput) > 0 {
+		return nil, fmt.Errorf("failed to list local tags: %w", err)
+	}
+
+	remoteCmd := exec.Command("git", "ls-remote", "--tags", DefaultRemote)
+	remoteOutput, err := remoteCmd.CombinedOutput()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list remote tags: %v - %s", err, string(remoteOutput))
+	}
+
+	return CompareTagCommits(ParseTagCommits(string(localOutput)), ParseTagCommits(string(remoteOutput))), nil
+}
+
+// ParseTagCommits parses the output of `git show-ref --tags --dereference` or `git ls-remote --tags`
+// into the commit of every tag, using the peeled commit of annotated tags.
+func ParseTagCommits(output string) map[string]string {
+	commits := make(map[string]string)
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			continue
+		}
+		hash, ref := fields[0], fields[1]
+		name, ok := strings.CutPrefix(ref, "refs/tags/")
+		if !ok {
+			continue
+		}
+
+		if peeled, ok := strings.CutSuffix(name, "^{}"); ok {
+			commits[peeled] = hash
+		} else if _, ok := commits[name]; !ok {
+			commits[name] = hash
+		}
+	}
+	return commits
+}
+
+// CompareTagCommits classifies the tags that differ between the local and the remote tag commits, ordered by name.
+func CompareTagCommits(local, remote map[string]string) []TagSync {
+	var tags []TagSync
+	for name, commit := range local {
+		remoteCommit, ok := remote[name]
+		switch {
+		case !ok:
+			tags = append(tags, TagSync{Name: name, State: TagLocalOnly, Local: commit})
+		case remoteCommit != commit:
+			tags = append(tags, TagSync{Name: name, State: TagDiverged, Local: commit, Remote: remoteCommit})
+		}
+	}
+	for name, commit := range remote {
+		if _, ok := local[name]; !ok {
+			tags = append(tags, TagSync{Name: name, State: TagRemoteOnly, Remote: commit})
+		}
+	}
+
+	slices.SortFunc(tags, func(a, b TagSync) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	return tags
+}
+
+// FetchGitTag fetches a single tag from the default remote. With force a diverged local tag is overwritten.
+func (gs *GitState) FetchGitTag(tag string, force bool) error {
+	refspec := "refs/tags/" + tag + ":refs/tags/" + tag
+	if force {
+		refspec = "+" + refspec
+	}
+	cmd := exec.Command("git", "fetch", "--no-tags", DefaultRemote, refspec)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("error fetching git tag: %v - %s", err, string(output))
+	}
+	return nil
+}
+
+// ForcePushGitTag pushes a tag to the default remote, replacing the remote tag when it points elsewhere.
+func (gs *GitState) ForcePushGitTag(tag string) error {
+	cmd := exec.Command("git", "push", "--force", DefaultRemote, "refs/tags/"+tag)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("error pushing git tag: %v - %s", err, string(output))
+	}
+	return nil
+}
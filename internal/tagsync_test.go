@@ -0,0 +1,33 @@
+package internal
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestParseTagCommits tests that annotated tags resolve to the commit they point to
+func TestParseTagCommits(t *testing.T) {
+	lsRemote := "aaa\trefs/tags/v1.0.0\n" +
+		"ttt\trefs/tags/v1.1.0\n" +
+		"bbb\trefs/tags/v1.1.0^{}\n" +
+		"ccc\trefs/heads/main\n"
+	assert.Equal(t, map[string]string{"v1.0.0": "aaa", "v1.1.0": "bbb"}, ParseTagCommits(lsRemote))
+
+	showRef := "aaa refs/tags/v1.0.0\nttt refs/tags/v1.1.0\nbbb refs/tags/v1.1.0^{}\n"
+	assert.Equal(t, map[string]string{"v1.0.0": "aaa", "v1.1.0": "bbb"}, ParseTagCommits(showRef))
+}
+
+// TestCompareTagCommits tests the classification of tags missing on one side or pointing to different commits
+func TestCompareTagCommits(t *testing.T) {
+	local := map[string]string{"v1.0.0": "aaa", "v1.1.0": "bbb", "v1.2.0": "ddd"}
+	remote := map[string]string{"v1.0.0": "aaa", "v1.1.0": "ccc", "v0.9.0": "eee"}
+
+	assert.Equal(t, []TagSync{
+		{Name: "v0.9.0", State: TagRemoteOnly, Remote: "eee"},
+		{Name: "v1.1.0", State: TagDiverged, Local: "bbb", Remote: "ccc"},
+		{Name: "v1.2.0", State: TagLocalOnly, Local: "ddd"},
+	}, CompareTagCommits(local, remote))
+
+	assert.Empty(t, CompareTagCommits(local, local))
+}
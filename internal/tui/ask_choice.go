@@ -0,0 +1,59 @@
+package tui
+
+import (
+	"github.com/charmbracelet/huh"
+)
+
+// Choice is an answer offered by AskChoice.
+type Choice struct {
+	Label string
+	Value string
+}
+
+type askChoiceOpts struct {
+	question     string
+	choices      []Choice
+	bypass       bool
+	defaultValue string
+}
+
+type AskChoiceOpt func(*askChoiceOpts)
+
+// ChooseIf skips the question and returns value when enabled.
+func ChooseIf(enabled bool, value string) AskChoiceOpt {
+	return func(o *askChoiceOpts) {
+		o.bypass = enabled
+		o.defaultValue = value
+	}
+}
+
+// AskChoice asks the user to pick one of the choices and returns its value, or an empty string when the question is cancelled
+func AskChoice(q string, choices []Choice, opts ...AskChoiceOpt) (value string) {
+	o := askChoiceOpts{
+		question: q,
+		choices:  choices,
+	}
+
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	if o.bypass {
+		return o.defaultValue
+	}
+
+	options := make([]huh.Option[string], 0, len(o.choices))
+	for _, c := range o.choices {
+		options = append(options, huh.NewOption(c.Label, c.Value))
+	}
+
+	err := huh.NewSelect[string]().
+		Title(o.question).
+		Options(options...).
+		Value(&value).
+		WithTheme(huh.ThemeBase()).Run()
+	if err != nil {
+		return ""
+	}
+	return value
+}
@@ -0,0 +1,55 @@
+package tui
+
+import (
+	"github.com/charmbracelet/huh"
+)
+
+type askMultiSelectOpts struct {
+	question string
+	choices  []Choice
+	bypass   bool
+}
+
+type AskMultiSelectOpt func(*askMultiSelectOpts)
+
+// SelectAllIf skips the question and returns every choice when enabled.
+func SelectAllIf(enabled bool) AskMultiSelectOpt {
+	return func(o *askMultiSelectOpts) {
+		o.bypass = enabled
+	}
+}
+
+// AskMultiSelect asks the user to pick any of the choices, all selected at first, and returns their values.
+// Nothing is returned when the question is cancelled.
+func AskMultiSelect(q string, choices []Choice, opts ...AskMultiSelectOpt) (values []string) {
+	o := askMultiSelectOpts{
+		question: q,
+		choices:  choices,
+	}
+
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	if o.bypass {
+		for _, c := range o.choices {
+			values = append(values, c.Value)
+		}
+		return values
+	}
+
+	options := make([]huh.Option[string], 0, len(o.choices))
+	for _, c := range o.choices {
+		options = append(options, huh.NewOption(c.Label, c.Value).Selected(true))
+	}
+
+	err := huh.NewMultiSelect[string]().
+		Title(o.question).
+		Options(options...).
+		Value(&values).
+		WithTheme(huh.ThemeBase()).Run()
+	if err != nil {
+		return nil
+	}
+	return values
+}
@@ -0,0 +1,206 @@
+package internal
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"slices"
+	"strings"
+)
+
+// Tag history checks run by bump verify.
+const (
+	TagCheckGaps        = "gaps"
+	TagCheckDuplicates  = "duplicates"
+	TagCheckOrder       = "order"
+	TagCheckBranch      = "default_branch"
+	TagCheckClashes     = "branch_clashes"
+	TagCheckLightweight = "lightweight"
+)
+
+// TagProblem is an issue found in the tag history.
+type TagProblem struct {
+	Check   string
+	Tag     string
+	Message string
+}
+
+// VersionGaps reports stable versions that do not directly follow the previous stable version,
+// e.g. v1.2.5 after v1.2.3. Pre-releases are ignored.
+func VersionGaps(tags []TagInfo) []TagProblem {
+	var problems []TagProblem
+	var prev *TagInfo
+
+	for i := range tags {
+		t := &tags[i]
+		if t.Version.Prerelease() != "" {
+			continue
+		}
+		if prev != nil && !t.Version.Equal(prev.Version) {
+			nextPatch, nextMinor, nextMajor := prev.Version.IncPatch(), prev.Version.IncMinor(), prev.Version.IncMajor()
+			if !t.Version.Equal(&nextPatch) && !t.Version.Equal(&nextMinor) && !t.Version.Equal(&nextMajor) {
+				problems = append(problems, TagProblem{
+					Check: TagCheckGaps,
+					Tag:   t.Name,
+					Message: fmt.Sprintf("%s follows %s, expected %s, %s or %s",
+						t.Name, prev.Name, nextPatch.String(), nextMinor.String(), nextMajor.String()),
+				})
+			}
+		}
+		prev = t
+	}
+
+	return problems
+}
+
+// DuplicateVersions reports tags that normalise to the same version, e.g. v1.2.3 and 1.2.3.
+func DuplicateVersions(tags []TagInfo) []TagProblem {
+	byVersion := make(map[string][]string)
+	var order []string
+	for _, t := range tags {
+		v := t.Version.String()
+		if _, ok := byVersion[v]; !ok {
+			order = append(order, v)
+		}
+		byVersion[v] = append(byVersion[v], t.Name)
+	}
+
+	var problems []TagProblem
+	for _, v := range order {
+		if names := byVersion[v]; len(names) > 1 {
+			problems = append(problems, TagProblem{
+				Check:   TagCheckDuplicates,
+				Tag:     names[0],
+				Message: fmt.Sprintf("%s all normalise to %s", strings.Join(names, ", "), v),
+			})
+		}
+	}
+	return problems
+}
+
+// AncestryOrder reports versions whose commit is an ancestor of the commit of the previous version,
+// i.e. the version order disagrees with the commit history.
+func AncestryOrder(tags []TagInfo, isAncestor func(ancestor, commit string) (bool, error)) ([]TagProblem, error) {
+	var problems []TagProblem
+	for i := 1; i < len(tags); i++ {
+		prev, t := tags[i-1], tags[i]
+		if t.Commit == prev.Commit || t.Version.Equal(prev.Version) {
+			continue
+		}
+
+		older, err := isAncestor(t.Commit, prev.Commit)
+		if err != nil {
+			return nil, err
+		}
+		if older {
+			problems = append(problems, TagProblem{
+				Check:   TagCheckOrder,
+				Tag:     t.Name,
+				Message: fmt.Sprintf("%s points to a commit before %s", t.Name, prev.Name),
+			})
+		}
+	}
+	return problems, nil
+}
+
+// OutsideBranch reports tags whose commit is not part of the history of the branch ref.
+func OutsideBranch(tags []TagInfo, ref string, isAncestor func(ancestor, commit string) (bool, error)) ([]TagProblem, error) {
+	var problems []TagProblem
+	for _, t := range tags {
+		onBranch, err := isAncestor(t.Commit, ref)
+		if err != nil {
+			return nil, err
+		}
+		if !onBranch {
+			problems = append(problems, TagProblem{
+				Check:   TagCheckBranch,
+				Tag:     t.Name,
+				Message: fmt.Sprintf("%s points to %s which is not on %s", t.Name, shortHash(t.Commit), shortRef(ref)),
+			})
+		}
+	}
+	return problems, nil
+}
+
+// BranchClashes reports tags named like a branch, which makes the name ambiguous for git.
+func BranchClashes(tags []TagInfo, branches []string) []TagProblem {
+	var problems []TagProblem
+	for _, t := range tags {
+		if slices.Contains(branches, t.Name) {
+			problems = append(problems, TagProblem{
+				Check:   TagCheckClashes,
+				Tag:     t.Name,
+				Message: fmt.Sprintf("%s is also the name of a branch", t.Name),
+			})
+		}
+	}
+	return problems
+}
+
+// LightweightTags reports tags that are not annotated.
+func LightweightTags(tags []TagInfo) []TagProblem {
+	var problems []TagProblem
+	for _, t := range tags {
+		if !t.Annotated {
+			problems = append(problems, TagProblem{
+				Check:   TagCheckLightweight,
+				Tag:     t.Name,
+				Message: fmt.Sprintf("%s is a lightweight tag", t.Name),
+			})
+		}
+	}
+	return problems
+}
+
+// IsAncestor reports whether the first commit is part of the history of the second one.
+func IsAncestor(ancestor, commit string) (bool, error) {
+	cmd := exec.Command("git", "merge-base", "--is-ancestor", ancestor, commit)
+	output, err := cmd.CombinedOutput()
+	if err == nil {
+		return true, nil
+	}
+
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() == 1 {
+		return false, nil
+	}
+	return false, fmt.Errorf("error comparing %s with %s: %v - %s", ancestor, commit, err, string(output))
+}
+
+// BranchNames returns the names of the local branches and of the remote branches without their remote prefix.
+func BranchNames() ([]string, error) {
+	cmd := exec.Command("git", "for-each-ref", "--format=%(refname)", "refs/heads", "refs/remotes")
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("error listing branches: %w", err)
+	}
+
+	var names []string
+	for _, ref := range strings.Fields(string(output)) {
+		var name string
+		if after, ok := strings.CutPrefix(ref, "refs/heads/"); ok {
+			name = after
+		} else if after, ok := strings.CutPrefix(ref, "refs/remotes/"); ok {
+			_, name, _ = strings.Cut(after, "/")
+		}
+		if name != "" && name != "HEAD" && !slices.Contains(names, name) {
+			names = append(names, name)
+		}
+	}
+	return names, nil
+}
+
+// shortRef strips the namespace of a branch ref, e.g. refs/remotes/origin/main becomes origin/main.
+func shortRef(ref string) string {
+	if after, ok := strings.CutPrefix(ref, "refs/heads/"); ok {
+		return after
+	}
+	return strings.TrimPrefix(ref, "refs/remotes/")
+}
+
+func shortHash(hash string) string {
+	if len(hash) > 7 {
+		return hash[:7]
+	}
+	return hash
+}
@@ -0,0 +1,83 @@
+package internal
+
+import (
+	"testing"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/stretchr/testify/assert"
+)
+
+func verifyTags(names ...string) []TagInfo {
+	tags := make([]TagInfo, len(names))
+	for i, name := range names {
+		tags[i] = TagInfo{Name: name, Version: semver.MustParse(name), Commit: name, Annotated: true}
+	}
+	return tags
+}
+
+// TestVersionGaps tests that skipped stable versions are reported and pre-releases are ignored
+func TestVersionGaps(t *testing.T) {
+	problems := VersionGaps(verifyTags("v1.2.3", "v1.2.4-rc.1", "v1.2.4", "v1.3.0", "v2.0.0", "v2.0.2", "v3.1.0"))
+	if assert.Len(t, problems, 2) {
+		assert.Equal(t, "v2.0.2", problems[0].Tag)
+		assert.Equal(t, "v3.1.0", problems[1].Tag)
+		assert.Equal(t, TagCheckGaps, problems[0].Check)
+	}
+
+	assert.Empty(t, VersionGaps(verifyTags("v1.0.0", "v1.0.1", "v1.1.0")))
+}
+
+// TestDuplicateVersions tests that tags normalising to the same version are reported once
+func TestDuplicateVersions(t *testing.T) {
+	problems := DuplicateVersions(verifyTags("v1.2.2", "v1.2.3", "1.2.3"))
+	if assert.Len(t, problems, 1) {
+		assert.Equal(t, "v1.2.3", problems[0].Tag)
+		assert.Contains(t, problems[0].Message, "v1.2.3, 1.2.3")
+	}
+
+	assert.Empty(t, DuplicateVersions(verifyTags("v1.2.2", "v1.2.3")))
+}
+
+// TestAncestryOrder tests that a version tagging an older commit than the previous version is reported
+func TestAncestryOrder(t *testing.T) {
+	history := []string{"v1.0.0", "v1.2.0", "v1.1.0"}
+	isAncestor := func(ancestor, commit string) (bool, error) {
+		a, c := -1, -1
+		for i, h := range history {
+			if h == ancestor {
+				a = i
+			}
+			if h == commit {
+				c = i
+			}
+		}
+		return a <= c, nil
+	}
+
+	problems, err := AncestryOrder(verifyTags("v1.0.0", "v1.1.0", "v1.2.0"), isAncestor)
+	assert.NoError(t, err)
+	if assert.Len(t, problems, 1) {
+		assert.Equal(t, "v1.2.0", problems[0].Tag)
+	}
+
+	history = []string{"v1.0.0", "v1.1.0", "v1.2.0"}
+	problems, err = AncestryOrder(verifyTags("v1.0.0", "v1.1.0", "v1.2.0"), isAncestor)
+	assert.NoError(t, err)
+	assert.Empty(t, problems)
+}
+
+// TestBranchClashesAndLightweightTags tests the checks on tag names and tag objects
+func TestBranchClashesAndLightweightTags(t *testing.T) {
+	tags := verifyTags("v1.0.0", "v1.1.0")
+	tags[1].Annotated = false
+
+	problems := BranchClashes(tags, []string{"main", "v1.0.0"})
+	if assert.Len(t, problems, 1) {
+		assert.Equal(t, "v1.0.0", problems[0].Tag)
+	}
+
+	problems = LightweightTags(tags)
+	if assert.Len(t, problems, 1) {
+		assert.Equal(t, "v1.1.0", problems[0].Tag)
+	}
+}
@@ -0,0 +1,56 @@
+package internal
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+)
+
+// DefaultPreID is the identifier of the first pre-release of a stable version, e.g. 1.2.4-rc.0.
+const DefaultPreID = "rc"
+
+// IncPrerelease returns the next pre-release of the version.
+// A stable version gets the first pre-release of its next patch (1.2.3 -> 1.2.4-rc.0),
+// a pre-release with the same identifier is counted up (1.2.4-rc.0 -> 1.2.4-rc.1)
+// and a pre-release with another identifier starts over (1.2.4-alpha.3 -> 1.2.4-rc.0).
+// Starting over with an identifier that sorts before the current one is an error, 1.2.4-alpha.0 is lower than 1.2.4-rc.1.
+func IncPrerelease(v *semver.Version, id string) (semver.Version, error) {
+	if v.Prerelease() == "" {
+		next := v.IncPatch()
+		return next.SetPrerelease(joinPrerelease(id, "0"))
+	}
+
+	parts := strings.Split(v.Prerelease(), ".")
+	if id != "" && parts[0] != id {
+		next, err := withoutMetadata(v).SetPrerelease(joinPrerelease(id, "0"))
+		if err != nil {
+			return next, err
+		}
+		if !next.GreaterThan(v) {
+			return semver.Version{}, fmt.Errorf("pre-release %s would be lower than %s, choose an identifier that sorts after %s", next.String(), v.String(), parts[0])
+		}
+		return next, nil
+	}
+
+	last := len(parts) - 1
+	if n, err := strconv.ParseUint(parts[last], 10, 64); err == nil {
+		parts[last] = strconv.FormatUint(n+1, 10)
+	} else {
+		parts = append(parts, "0")
+	}
+	return withoutMetadata(v).SetPrerelease(strings.Join(parts, "."))
+}
+
+func joinPrerelease(id, n string) string {
+	if id == "" {
+		return n
+	}
+	return id + "." + n
+}
+
+func withoutMetadata(v *semver.Version) semver.Version {
+	out, _ := v.SetMetadata("")
+	return out
+}
@@ -0,0 +1,43 @@
+package internal
+
+import (
+	"testing"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestIncPrerelease tests how pre-releases are started and counted up
+func TestIncPrerelease(t *testing.T) {
+	testCases := []struct {
+		name     string
+		version  string
+		id       string
+		expected string
+	}{
+		{name: "Stable version", version: "1.2.3", id: "rc", expected: "1.2.4-rc.0"},
+		{name: "Stable version without identifier", version: "1.2.3", expected: "1.2.4-0"},
+		{name: "Same identifier", version: "1.2.4-rc.0", id: "rc", expected: "1.2.4-rc.1"},
+		{name: "Another identifier", version: "1.2.4-alpha.3", id: "rc", expected: "1.2.4-rc.0"},
+		{name: "Keep identifier", version: "1.2.4-beta.9", expected: "1.2.4-beta.10"},
+		{name: "No counter", version: "1.2.4-rc", id: "rc", expected: "1.2.4-rc.0"},
+		{name: "Metadata is dropped", version: "1.2.4-rc.1+build.5", id: "rc", expected: "1.2.4-rc.2"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			next, err := IncPrerelease(semver.MustParse(tc.version), tc.id)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, next.String())
+		})
+	}
+}
+
+// TestIncPrereleaseLowerIdentifier tests that switching to an identifier that sorts lower is rejected
+func TestIncPrereleaseLowerIdentifier(t *testing.T) {
+	_, err := IncPrerelease(semver.MustParse("1.2.4-rc.1"), "alpha")
+	assert.ErrorContains(t, err, "1.2.4-alpha.0 would be lower than 1.2.4-rc.1")
+
+	_, err = IncPrerelease(semver.MustParse("1.2.4-rc.1+build.5"), "beta")
+	assert.Error(t, err)
+}
@@ -0,0 +1,336 @@
+package internal
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go/format"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+	"gopkg.in/yaml.v3"
+)
+
+// Statuses of a version file compared with the latest tag by VersionFile.Check.
+const (
+	VersionFileOk       = "ok"
+	VersionFileMismatch = "mismatch"
+	VersionFileInvalid  = "invalid version"
+)
+
+// VersionFile is a file that carries the project version, such as package.json or Chart.yaml.
+// At most one of the format fields is set; when none is, the whole file content is the version.
+type VersionFile struct {
+	Path string `yaml:"path"`
+	// JSON is a dot separated path of object keys, e.g. "version"
+	JSON string `yaml:"json"`
+	// YAML is a dot separated path of mapping keys, e.g. "appVersion"
+	YAML string `yaml:"yaml"`
+	// TOML is a dotted key including its table, e.g. "package.version"
+	TOML string `yaml:"toml"`
+	// Regex matches the version; its first capture group, if any, is the version itself
+	Regex string `yaml:"regex"`
+	// Go is the name of a package-level string var or const in a Go source file, e.g. "Version"
+	Go string `yaml:"go"`
+}
+
+// Format returns a short name of the way the version is located in the file.
+func (f VersionFile) Format() string {
+	switch {
+	case f.JSON != "":
+		return "json"
+	case f.YAML != "":
+		return "yaml"
+	case f.TOML != "":
+		return "toml"
+	case f.Regex != "":
+		return "regex"
+	case f.Go != "":
+		return "go"
+	default:
+		return "plain"
+	}
+}
+
+// ReadVersion returns the version currently written in the file.
+func (f VersionFile) ReadVersion() (string, error) {
+	data, err := os.ReadFile(f.Path)
+	if err != nil {
+		return "", err
+	}
+
+	start, end, err := f.locate(data)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", f.Path, err)
+	}
+	return string(data[start:end]), nil
+}
+
+// Check compares the version written in the file with ver. It returns the version found, or "-" when
+// it cannot be read, and a status: VersionFileOk, VersionFileMismatch, VersionFileInvalid or the read error.
+func (f VersionFile) Check(ver *semver.Version) (string, string) {
+	found, err := f.ReadVersion()
+	if err != nil {
+		return "-", err.Error()
+	}
+
+	fileVer, err := semver.NewVersion(found)
+	if err != nil {
+		return found, VersionFileInvalid
+	}
+	if !fileVer.Equal(ver) {
+		return found, VersionFileMismatch
+	}
+	return found, VersionFileOk
+}
+
+// Render returns the file content with the version replaced, without writing it. A v prefix of the
+// version in the file is kept, as Check accepts either form. It reports whether the content differs from the file on disk.
+func (f VersionFile) Render(version string) ([]byte, bool, error) {
+	data, err := os.ReadFile(f.Path)
+	if err != nil {
+		return nil, false, err
+	}
+
+	start, end, err := f.locate(data)
+	if err != nil {
+		return nil, false, fmt.Errorf("%s: %w", f.Path, err)
+	}
+	if strings.HasPrefix(string(data[start:end]), "v") && !strings.HasPrefix(version, "v") {
+		version = "v" + version
+	}
+	if string(data[start:end]) == version {
+		return data, false, nil
+	}
+
+	out := make([]byte, 0, len(data)-(end-start)+len(version))
+	out = append(out, data[:start]...)
+	out = append(out, version...)
+	out = append(out, data[end:]...)
+
+	if f.Format() == "go" {
+		out, err = format.Source(out)
+		if err != nil {
+			return nil, false, fmt.Errorf("%s: failed to format go source: %w", f.Path, err)
+		}
+	}
+	return out, true, nil
+}
+
+// locate returns the byte range [start, end) of the version in the file content.
+func (f VersionFile) locate(data []byte) (int, int, error) {
+	switch f.Format() {
+	case "json":
+		return locateJSON(data, strings.Split(f.JSON, "."))
+	case "yaml":
+		return locateYAML(data, strings.Split(f.YAML, "."))
+	case "toml":
+		return locateTOML(data, f.TOML)
+	case "regex":
+		return locateRegex(data, f.Regex)
+	case "go":
+		return locateGo(data, f.Go)
+	default:
+		trimmed := bytes.TrimSpace(data)
+		if len(trimmed) == 0 {
+			return 0, 0, errors.New("file is empty")
+		}
+		start := bytes.Index(data, trimmed)
+		return start, start + len(trimmed), nil
+	}
+}
+
+// jsonFrame is an open JSON object or array while scanning tokens.
+type jsonFrame struct {
+	object    bool
+	key       string
+	expectKey bool
+}
+
+func locateJSON(data []byte, path []string) (int, int, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	var stack []*jsonFrame
+
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return 0, 0, fmt.Errorf("json path '%s' not found", strings.Join(path, "."))
+			}
+			return 0, 0, fmt.Errorf("invalid json: %w", err)
+		}
+
+		var top *jsonFrame
+		if len(stack) > 0 {
+			top = stack[len(stack)-1]
+		}
+
+		if delim, ok := tok.(json.Delim); ok {
+			switch delim {
+			case '{', '[':
+				stack = append(stack, &jsonFrame{object: delim == '{', expectKey: delim == '{'})
+			default:
+				stack = stack[:len(stack)-1]
+				if len(stack) > 0 && stack[len(stack)-1].object {
+					stack[len(stack)-1].expectKey = true
+				}
+			}
+			continue
+		}
+
+		if top == nil || !top.object {
+			continue
+		}
+		if !top.expectKey {
+			top.expectKey = true
+			continue
+		}
+
+		top.key, _ = tok.(string)
+		top.expectKey = false
+		if !jsonPathMatches(stack, path) {
+			continue
+		}
+
+		before := dec.InputOffset()
+		value, err := dec.Token()
+		if err != nil {
+			return 0, 0, fmt.Errorf("invalid json: %w", err)
+		}
+		if _, ok := value.(string); !ok {
+			return 0, 0, fmt.Errorf("json path '%s' is not a string", strings.Join(path, "."))
+		}
+		after := int(dec.InputOffset())
+		quote := bytes.IndexByte(data[before:after], '"')
+		return int(before) + quote + 1, after - 1, nil
+	}
+}
+
+func jsonPathMatches(stack []*jsonFrame, path []string) bool {
+	if len(stack) != len(path) {
+		return false
+	}
+	for i, frame := range stack {
+		if !frame.object || frame.key != path[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func locateYAML(data []byte, path []string) (int, int, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return 0, 0, fmt.Errorf("invalid yaml: %w", err)
+	}
+	if len(doc.Content) == 0 {
+		return 0, 0, errors.New("yaml document is empty")
+	}
+
+	node := doc.Content[0]
+	for _, key := range path {
+		if node.Kind != yaml.MappingNode {
+			return 0, 0, fmt.Errorf("yaml path '%s' not found", strings.Join(path, "."))
+		}
+		var next *yaml.Node
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			if node.Content[i].Value == key {
+				next = node.Content[i+1]
+				break
+			}
+		}
+		if next == nil {
+			return 0, 0, fmt.Errorf("yaml path '%s' not found", strings.Join(path, "."))
+		}
+		node = next
+	}
+
+	if node.Kind != yaml.ScalarNode {
+		return 0, 0, fmt.Errorf("yaml path '%s' is not a scalar", strings.Join(path, "."))
+	}
+
+	start := lineOffset(data, node.Line) + node.Column - 1
+	if node.Style&(yaml.DoubleQuotedStyle|yaml.SingleQuotedStyle) != 0 {
+		start++
+	}
+	end := start + len(node.Value)
+	if end > len(data) || string(data[start:end]) != node.Value {
+		return 0, 0, fmt.Errorf("yaml path '%s' cannot be rewritten in place", strings.Join(path, "."))
+	}
+	return start, end, nil
+}
+
+// lineOffset returns the byte offset of the beginning of the 1-based line.
+func lineOffset(data []byte, line int) int {
+	offset := 0
+	for i := 1; i < line; i++ {
+		next := bytes.IndexByte(data[offset:], '\n')
+		if next < 0 {
+			return len(data)
+		}
+		offset += next + 1
+	}
+	return offset
+}
+
+var tomlTableRe = regexp.MustCompile(`^\s*\[\[?\s*([^\[\]]+?)\s*\]\]?\s*(#.*)?$`)
+
+func locateTOML(data []byte, key string) (int, int, error) {
+	table := ""
+	offset := 0
+
+	for _, line := range strings.SplitAfter(string(data), "\n") {
+		lineStart := offset
+		offset += len(line)
+
+		if m := tomlTableRe.FindStringSubmatch(line); m != nil {
+			table = m[1]
+			continue
+		}
+
+		k, v, ok := strings.Cut(line, "=")
+		if !ok {
+			continue
+		}
+		k = strings.TrimSpace(k)
+		if table != "" {
+			k = table + "." + k
+		}
+		if k != key {
+			continue
+		}
+
+		v = strings.TrimLeft(v, " \t")
+		if v == "" || (v[0] != '"' && v[0] != '\'') {
+			return 0, 0, fmt.Errorf("toml key '%s' is not a string", key)
+		}
+		closing := strings.IndexByte(v[1:], v[0])
+		if closing < 0 {
+			return 0, 0, fmt.Errorf("toml key '%s' has an unterminated string", key)
+		}
+		start := lineStart + len(line) - len(v) + 1
+		return start, start + closing, nil
+	}
+
+	return 0, 0, fmt.Errorf("toml key '%s' not found", key)
+}
+
+func locateRegex(data []byte, expr string) (int, int, error) {
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid regex: %w", err)
+	}
+
+	loc := re.FindSubmatchIndex(data)
+	if loc == nil {
+		return 0, 0, fmt.Errorf("regex '%s' does not match", expr)
+	}
+	if len(loc) >= 4 && loc[2] >= 0 {
+		return loc[2], loc[3], nil
+	}
+	return loc[0], loc[1], nil
+}
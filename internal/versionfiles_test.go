@@ -0,0 +1,216 @@
+package internal
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestVersionFileRenderFormats tests that every format reads and rewrites only the version
+func TestVersionFileRenderFormats(t *testing.T) {
+	testCases := []struct {
+		name     string
+		content  string
+		file     VersionFile
+		expected string
+	}{
+		{
+			name:     "Plain",
+			content:  "1.2.3\n",
+			file:     VersionFile{},
+			expected: "1.3.0\n",
+		},
+		{
+			name:     "JSON",
+			content:  "{\n  \"name\": \"app\",\n  \"deps\": {\"version\": \"9.9.9\"},\n  \"version\":  \"1.2.3\",\n  \"list\": [{\"version\": \"0.0.1\"}]\n}\n",
+			file:     VersionFile{JSON: "version"},
+			expected: "{\n  \"name\": \"app\",\n  \"deps\": {\"version\": \"9.9.9\"},\n  \"version\":  \"1.3.0\",\n  \"list\": [{\"version\": \"0.0.1\"}]\n}\n",
+		},
+		{
+			name:     "Nested JSON",
+			content:  `{"deps": {"version": "1.2.3"}, "version": "0.0.1"}`,
+			file:     VersionFile{JSON: "deps.version"},
+			expected: `{"deps": {"version": "1.3.0"}, "version": "0.0.1"}`,
+		},
+		{
+			name:     "YAML",
+			content:  "apiVersion: v2\n# app version\nversion: 0.1.0\nappVersion: \"1.2.3\" # keep\n",
+			file:     VersionFile{YAML: "appVersion"},
+			expected: "apiVersion: v2\n# app version\nversion: 0.1.0\nappVersion: \"1.3.0\" # keep\n",
+		},
+		{
+			name:     "TOML",
+			content:  "[tool.other]\nversion = \"9.9.9\"\n\n[package]\nname = \"app\"\nversion = '1.2.3' # keep\n",
+			file:     VersionFile{TOML: "package.version"},
+			expected: "[tool.other]\nversion = \"9.9.9\"\n\n[package]\nname = \"app\"\nversion = '1.3.0' # keep\n",
+		},
+		{
+			name:     "Regex",
+			content:  "package main\n\nconst Version = \"1.2.3\"\n",
+			file:     VersionFile{Regex: `Version = "([^"]+)"`},
+			expected: "package main\n\nconst Version = \"1.3.0\"\n",
+		},
+		{
+			name:     "Go",
+			content:  "package main\n\nconst (\n\tName    = \"app\"\n\tVersion = \"1.2.3\" // set by bump\n)\n\nvar version = Version\n",
+			file:     VersionFile{Go: "Version"},
+			expected: "package main\n\nconst (\n\tName    = \"app\"\n\tVersion = \"1.3.0\" // set by bump\n)\n\nvar version = Version\n",
+		},
+		{
+			name:     "Go var in a multi-name spec",
+			content:  "package main\n\nvar commit, version = \"none\", \"1.2.3\"\n",
+			file:     VersionFile{Go: "version"},
+			expected: "package main\n\nvar commit, version = \"none\", \"1.3.0\"\n",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.file.Path = filepath.Join(t.TempDir(), "version")
+			assert.NoError(t, os.WriteFile(tc.file.Path, []byte(tc.content), 0o644))
+
+			ver, err := tc.file.ReadVersion()
+			assert.NoError(t, err)
+			assert.Equal(t, "1.2.3", ver)
+
+			out, changed, err := tc.file.Render("1.3.0")
+			assert.NoError(t, err)
+			assert.True(t, changed)
+			assert.Equal(t, tc.expected, string(out))
+			assert.NoError(t, os.WriteFile(tc.file.Path, out, 0o644))
+
+			out, changed, err = tc.file.Render("1.3.0")
+			assert.NoError(t, err)
+			assert.False(t, changed)
+			assert.Equal(t, tc.expected, string(out))
+		})
+	}
+}
+
+// TestVersionFileRender tests that rendering the new version leaves the file untouched
+func TestVersionFileRender(t *testing.T) {
+	f := VersionFile{Path: filepath.Join(t.TempDir(), "package.json"), JSON: "version"}
+	assert.NoError(t, os.WriteFile(f.Path, []byte(`{"version": "1.2.3"}`), 0o644))
+
+	out, changed, err := f.Render("1.3.0")
+	assert.NoError(t, err)
+	assert.True(t, changed)
+	assert.Equal(t, `{"version": "1.3.0"}`, string(out))
+
+	ver, err := f.ReadVersion()
+	assert.NoError(t, err)
+	assert.Equal(t, "1.2.3", ver)
+}
+
+// TestVersionFileRenderPrefix tests that a rendered file keeps its v prefix and is in sync with the version
+func TestVersionFileRenderPrefix(t *testing.T) {
+	f := VersionFile{Path: filepath.Join(t.TempDir(), "main.go"), Go: "Version"}
+	assert.NoError(t, os.WriteFile(f.Path, []byte("package main\n\nconst Version = \"v1.2.3\"\n"), 0o644))
+
+	_, changed, err := f.Render("1.2.3")
+	assert.NoError(t, err)
+	assert.False(t, changed)
+
+	out, changed, err := f.Render("1.3.0")
+	assert.NoError(t, err)
+	assert.True(t, changed)
+	assert.Equal(t, "package main\n\nconst Version = \"v1.3.0\"\n", string(out))
+	assert.NoError(t, os.WriteFile(f.Path, out, 0o644))
+
+	found, status := f.Check(semver.MustParse("1.3.0"))
+	assert.Equal(t, "v1.3.0", found)
+	assert.Equal(t, VersionFileOk, status)
+}
+
+// TestVersionFileErrors tests that missing or non-string values are reported
+func TestVersionFileErrors(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "package.json")
+	assert.NoError(t, os.WriteFile(path, []byte(`{"version": 1}`), 0o644))
+
+	_, err := VersionFile{Path: path, JSON: "version"}.ReadVersion()
+	assert.Error(t, err)
+
+	_, err = VersionFile{Path: path, JSON: "name"}.ReadVersion()
+	assert.Error(t, err)
+
+	gofile := filepath.Join(t.TempDir(), "main.go")
+	assert.NoError(t, os.WriteFile(gofile, []byte("package main\n\nvar version = buildVersion()\n\nfunc main() {\n\tconst Version = \"1.2.3\"\n}\n"), 0o644))
+
+	_, err = VersionFile{Path: gofile, Go: "version"}.ReadVersion()
+	assert.ErrorContains(t, err, "not a string literal")
+
+	_, err = VersionFile{Path: gofile, Go: "Version"}.ReadVersion()
+	assert.ErrorContains(t, err, "not found")
+}
+
+// TestVersionFileCheck tests the comparison of version files with the latest tag
+func TestVersionFileCheck(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		assert.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+		return path
+	}
+	ver := semver.MustParse("1.2.3")
+
+	testCases := []struct {
+		name          string
+		file          VersionFile
+		expectVersion string
+		expectStatus  string
+	}{
+		{
+			name:          "In sync",
+			file:          VersionFile{Path: write("VERSION", "1.2.3")},
+			expectVersion: "1.2.3",
+			expectStatus:  VersionFileOk,
+		},
+		{
+			name:          "In sync with prefix",
+			file:          VersionFile{Path: write("main.go", "package main\n\nconst Version = \"v1.2.3\"\n"), Go: "Version"},
+			expectVersion: "v1.2.3",
+			expectStatus:  VersionFileOk,
+		},
+		{
+			name:          "Drifted",
+			file:          VersionFile{Path: write("package.json", `{"version": "1.2.2"}`), JSON: "version"},
+			expectVersion: "1.2.2",
+			expectStatus:  VersionFileMismatch,
+		},
+		{
+			name:          "Drifted pre-release",
+			file:          VersionFile{Path: write("Chart.yaml", "appVersion: 1.2.3-rc.1\n"), YAML: "appVersion"},
+			expectVersion: "1.2.3-rc.1",
+			expectStatus:  VersionFileMismatch,
+		},
+		{
+			name:          "Invalid version",
+			file:          VersionFile{Path: write("Cargo.toml", "[package]\nversion = \"next\"\n"), TOML: "package.version"},
+			expectVersion: "next",
+			expectStatus:  VersionFileInvalid,
+		},
+		{
+			name:          "Missing file",
+			file:          VersionFile{Path: filepath.Join(dir, "missing.json"), JSON: "version"},
+			expectVersion: "-",
+			expectStatus:  "no such file or directory",
+		},
+		{
+			name:          "Missing key",
+			file:          VersionFile{Path: write("app.json", `{"name": "app"}`), JSON: "version"},
+			expectVersion: "-",
+			expectStatus:  "not found",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			found, status := tc.file.Check(ver)
+			assert.Equal(t, tc.expectVersion, found)
+			assert.Contains(t, status, tc.expectStatus)
+		})
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/fatih/color"
 	"github.com/flaticols/bump/cmd"
 	"github.com/flaticols/bump/internal"
+	"github.com/spf13/cobra"
 )
 
 var (
@@ -41,23 +42,33 @@ func main() {
 	rootCmd.PersistentFlags().BoolVarP(&opts.LocalRepo, "local", "l", false, "if local is set, bump will not error if no remotes are found")
 	rootCmd.PersistentFlags().BoolVarP(&opts.BraveMode, "brave", "b", false, "if brave is set, bump will not ask any questions (default: false)")
 	rootCmd.PersistentFlags().BoolVar(&opts.NoColor, "no-color", false, "disable colorful output (default: false)")
-
-	opts.Exit = func() {
-		if !opts.BraveMode {
-			os.Exit(1)
-		}
-
-		os.Exit(0)
-	}
+	rootCmd.PersistentFlags().StringVarP(&opts.Output, "output", "o", cmd.OutputText, "output format: text or json")
+	rootCmd.PersistentFlags().BoolVar(&opts.DryRun, "dry-run", false, "run the checks and print the planned changes without making them")
+	rootCmd.PersistentFlags().BoolVar(&opts.CI, "ci", false, "run in CI mode, detected from the environment by default")
+	rootCmd.PersistentFlags().StringVar(&opts.Events, "events", "", "write newline-delimited JSON events to a file path or file descriptor number")
 
 	undoCmd := cmd.CreateUndoCmd(opts)
 	rootCmd.AddCommand(undoCmd)
+	rootCmd.AddCommand(cmd.CreateAPIDiffCmd(opts))
+	rootCmd.AddCommand(cmd.CreateChangelogCmd(opts))
+	rootCmd.AddCommand(cmd.CreateCheckFilesCmd(opts))
+	rootCmd.AddCommand(cmd.CreateCurrentCmd(opts))
+	rootCmd.AddCommand(cmd.CreateNextCmd(opts))
+	rootCmd.AddCommand(cmd.CreateListCmd(opts))
+	rootCmd.AddCommand(cmd.CreateDiffCmd(opts))
+	rootCmd.AddCommand(cmd.CreateVerifyCmd(opts))
+	rootCmd.AddCommand(cmd.CreateSyncCmd(opts))
+	rootCmd.AddCommand(cmd.CreatePruneCmd(opts))
 
-	color.NoColor = opts.NoColor
+	// runs once the flags are parsed, the terminal color detection is kept unless --no-color is set
+	cobra.OnInitialize(func() {
+		if opts.NoColor {
+			color.NoColor = true
+		}
+	})
 
-	if err := rootCmd.Execute(); err != nil {
-		os.Exit(1)
-	}
+	rootCmd.SilenceErrors = true
+	os.Exit(cmd.Finish(opts, rootCmd.Execute()))
 }
 
 func versionPrinter(ver string) string {